@@ -2,16 +2,61 @@ package gospring
 
 import (
 	"container/list"
+	"context"
 	"fmt"
 	"reflect"
+	"sort"
+	"sync"
 )
 
 type applicationContext struct {
-	graph         *graph
-	beanById      map[string]BeanI
-	parentByChild map[BeanI]BeanI
-	singletons    map[BeanI]*reflect.Value
-	singletonList *list.List
+	parent   ApplicationContextI
+	beanById map[string]BeanI
+
+	// beans are top-level definitions in the order they are given
+	beans []BeanI
+
+	// profiles are names of active profiles, and disabledById are reasons
+	// why beans are removed by profiles or conditions.
+	profiles     map[string]bool
+	disabledById map[string]string
+
+	environment EnvironmentI
+
+	// lock guards singletons, singletonList and singletonLocks
+	lock           sync.RWMutex
+	singletons     map[BeanI]*reflect.Value
+	singletonList  *list.List
+	singletonLocks map[BeanI]*sync.Mutex
+
+	// eager is true if singletons are created by NewApplicationContext
+	eager bool
+
+	// allowCircular is true if singletons can depend on each other through
+	// properties. Singletons are then created by one goroutine at a time,
+	// which holds creationLock.
+	allowCircular bool
+	creationLock  creationLock
+
+	// scopes are registered by ContextBuilderI.Scope(...)
+	scopes map[Scope]ScopeI
+
+	// autowired are references of fields tagged by AutowireTag, by beans
+	// and names of fields
+	autowired map[BeanI]map[string]BeanI
+
+	// scopedLock guards scoped, which are objects created in scopes while
+	// singletons are pre-instantiated. It's nil otherwise.
+	scopedLock sync.Mutex
+	scoped     []scopedObject
+}
+
+// scopedObject is an object created in a scope, which is finalized if the
+// context fails to be built.
+type scopedObject struct {
+	bean  BeanI
+	name  string
+	scope ScopeI
 }
 
 // NewApplicationContext creates an ApplicationContextI object
@@ -22,47 +67,234 @@ type applicationContext struct {
 // )
 //
 // It is a creation function to create an instance with the
-// interface ApplicationContextI
+// interface ApplicationContextI. It is the same as Context().Build(...)
 func NewApplicationContext(beans ...BeanI) (ApplicationContextI, error) {
-	list.New()
+	return Context().Build(beans...)
+}
+
+func newApplicationContext(builder *contextBuilder, beans []BeanI) (*applicationContext, error) {
+
+	ctx, e := prepareApplicationContext(builder, beans)
+	if e != nil {
+		return nil, e
+	}
+
+	if e := ctx.preInstantiateSingletons(ctx.beans); e != nil {
+		return nil, e
+	}
+
+	return ctx, nil
+}
+
+// prepareApplicationContext creates a context whose beans are resolved and
+// validated, but none of them is created yet.
+func prepareApplicationContext(builder *contextBuilder, beans []BeanI) (*applicationContext, error) {
 	ctx := applicationContext{
-		graph:         newGraph(),
-		beanById:      make(map[string]BeanI),
-		parentByChild: make(map[BeanI]BeanI),
-		singletons:    make(map[BeanI]*reflect.Value),
-		singletonList: list.New(),
+		parent:         builder.parent,
+		beanById:       make(map[string]BeanI),
+		singletons:     make(map[BeanI]*reflect.Value),
+		singletonList:  list.New(),
+		singletonLocks: make(map[BeanI]*sync.Mutex),
+		eager:          builder.eager,
+		allowCircular:  builder.allowCircular,
+		scopes:         make(map[Scope]ScopeI),
+		autowired:      make(map[BeanI]map[string]BeanI),
+		profiles:       make(map[string]bool),
+		disabledById:   make(map[string]string),
+	}
+
+	sources := append([]PropertySourceI{}, builder.sources...)
+	if ctx.parent != nil {
+		sources = append(sources, ctx.parent.GetEnvironment())
+	}
+	ctx.environment = NewEnvironment(sources...)
+
+	for _, profile := range builder.profiles {
+		ctx.profiles[profile] = true
+	}
+
+	for name, scope := range builder.scopes {
+		switch name {
+		case Default, Singleton, Prototype, Request:
+			return nil, fmt.Errorf("Scope [%v] is built-in and can't be registered", name)
+		}
+		ctx.scopes[name] = scope
 	}
 
+	beans, e := ctx.filterByProfiles(beans)
+	if e != nil {
+		return nil, e
+	}
+
+	if beans, e = ctx.addBeans(beans); e != nil {
+		return nil, e
+	}
+	ctx.beans = beans
+
 	for _, bean := range beans {
-		if e := ctx.addBean(bean); e != nil {
-			return nil, fmt.Errorf("Can't add bean [%v]. Cuased by: %v", bean, e)
+		if e := ctx.autowire(bean); e != nil {
+			return nil, fmt.Errorf("Can't autowire bean [%v]. Cuased by: %w", bean, e)
 		}
 	}
 
+	// problems of references and dependency loops are reported together
+	// with problems found by the validator
+	var problems []error
+	unresolved := make(map[BeanI]bool)
 	for _, bean := range beans {
 		if e := ctx.setRefBean(bean); e != nil {
-			return nil, fmt.Errorf("Can't add bean [%v]. Cuased by: %v", bean, e)
+			problems = append(problems, fmt.Errorf("Can't add bean [%v]. Cuased by: %w", bean, e))
+			unresolved[bean] = true
 		}
 	}
 
-	for _, bean := range beans {
-		if e := ctx.checkDependencyLoop(bean); e != nil {
-			return nil, fmt.Errorf("Detect dependency loop. Cuased by: %v", e)
+	if len(unresolved) == 0 {
+		if e := ctx.checkDependencyLoop(); e != nil {
+			problems = append(problems, e)
 		}
 	}
 
+	if !builder.skipValidation {
+		problems = append(problems, ctx.validate(unresolved)...)
+	}
+
+	if len(problems) > 0 {
+		return nil, &ValidationError{Problems: problems}
+	}
+
 	return &ctx, nil
 }
 
+// preInstantiateSingletons creates all non-lazy singletons in the order of
+// beans. Dependencies are created before the bean depends on them. If any
+// of them fails, the singletons and the scoped objects created so far are
+// finalized, but registered scopes are left open, since they may be shared
+// by other contexts.
+func (ctx *applicationContext) preInstantiateSingletons(beans []BeanI) error {
+
+	ctx.scopedLock.Lock()
+	ctx.scoped = make([]scopedObject, 0)
+	ctx.scopedLock.Unlock()
+
+	defer func() {
+		ctx.scopedLock.Lock()
+		ctx.scoped = nil
+		ctx.scopedLock.Unlock()
+	}()
+
+	for _, bean := range beans {
+
+		if !ctx.isEager(bean) {
+			continue
+		}
+
+		if _, e := ctx.getBean(bean, nil, nil); e != nil {
+			ef := ctx.finalizeScopedObjects()
+			if es := ctx.finalizeSingletons(); ef == nil {
+				ef = es
+			}
+			if ef != nil {
+				return fmt.Errorf("Can't pre-instantiate bean [%v] and finalize created beans. Caused by: %v; %v",
+					beanName(bean), e, ef)
+			}
+			return fmt.Errorf("Can't pre-instantiate bean [%v]. Caused by: %w", beanName(bean), e)
+		}
+	}
+
+	return nil
+}
+
+// addScopedObject records the object created in the scope while singletons
+// are pre-instantiated.
+func (ctx *applicationContext) addScopedObject(bean BeanI, name string, scope ScopeI) {
+	ctx.scopedLock.Lock()
+	defer ctx.scopedLock.Unlock()
+
+	if ctx.scoped != nil {
+		ctx.scoped = append(ctx.scoped, scopedObject{bean: bean, name: name, scope: scope})
+	}
+}
+
+// finalizeScopedObjects removes objects created while singletons are
+// pre-instantiated from their scopes, and finalizes them in the reverse
+// order they are created. All of them are finalized even if some fail, and
+// the first error is returned.
+func (ctx *applicationContext) finalizeScopedObjects() error {
+
+	ctx.scopedLock.Lock()
+	scoped := ctx.scoped
+	ctx.scoped = nil
+	ctx.scopedLock.Unlock()
+
+	var first error
+	for i := len(scoped) - 1; i >= 0; i-- {
+		object := scoped[i].scope.Remove(scoped[i].name)
+		if object == nil {
+			continue
+		}
+		if e := ctx.callFinalizeFunc(reflect.ValueOf(object), scoped[i].bean); e != nil && first == nil {
+			first = fmt.Errorf(
+				"Can't call finalize function of bean [%v]. Caused by: %w",
+				scoped[i].bean, e)
+		}
+	}
+	return first
+}
+
+func (ctx *applicationContext) isEager(bean BeanI) bool {
+
+	sbean, ok := bean.(*structBean)
+	if !ok {
+		return false
+	}
+
+	switch sbean.GetScope() {
+	case Default:
+	case Singleton:
+	default:
+		return false
+	}
+
+	if lazy := sbean.GetLazy(); lazy != nil {
+		return !*lazy
+	}
+
+	return ctx.eager
+}
+
+// GetBean is safe for concurrent use. A singleton bean is created
+// only once even if many goroutines ask for it at the same time.
+//
+// If there is no bean with the ID, the bean is acquired from the parent
+// context.
 func (ctx *applicationContext) GetBean(id string) (interface{}, error) {
+	return ctx.getBeanByID(id, nil)
+}
+
+// GetBeanContext is the same as GetBean(...), but beans in the Request scope
+// are acquired from the request scope held by c.
+func (ctx *applicationContext) GetBeanContext(c context.Context, id string) (interface{}, error) {
+	return ctx.getBeanByID(id, &creation{context: c})
+}
+
+func (ctx *applicationContext) getBeanByID(id string, c *creation) (interface{}, error) {
 
 	bean, present := ctx.beanById[id]
 
 	if !present {
-		return nil, fmt.Errorf("There is no bean with ID [%v]", id)
+		if ctx.parent != nil {
+			if c != nil {
+				return ctx.parent.GetBeanContext(c.context, id)
+			}
+			return ctx.parent.GetBean(id)
+		}
+		if reason, disabled := ctx.disabledById[id]; disabled {
+			return nil, fmt.Errorf("Bean with ID [%v] is disabled by %v: %w", id, reason, ErrBeanNotFound)
+		}
+		return nil, fmt.Errorf("There is no bean with ID [%v]: %w", id, ErrBeanNotFound)
 	}
 
-	value, e := ctx.getBean(bean)
+	value, e := ctx.getBean(bean, nil, c)
 
 	if e != nil {
 		return nil, e
@@ -71,19 +303,174 @@ func (ctx *applicationContext) GetBean(id string) (interface{}, error) {
 	return value.Interface(), nil
 }
 
+// GetEnvironment returns the environment which contains property sources of
+// the context and its parent.
+func (ctx *applicationContext) GetEnvironment() EnvironmentI {
+	return ctx.environment
+}
+
+func (ctx *applicationContext) ContainsBean(id string) bool {
+	if _, present := ctx.beanById[id]; present {
+		return true
+	}
+	if ctx.parent != nil {
+		return ctx.parent.ContainsBean(id)
+	}
+	return false
+}
+
+// GetBeanIDs returns sorted IDs of beans defined in this context, not
+// including those of the parent context.
+func (ctx *applicationContext) GetBeanIDs() []string {
+	ids := make([]string, 0, len(ctx.beanById))
+	for id := range ctx.beanById {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func (ctx *applicationContext) GetBeanDefinition(id string) (BeanDefinitionI, error) {
+
+	bean, present := ctx.beanById[id]
+
+	if !present {
+		if ctx.parent != nil {
+			return ctx.parent.GetBeanDefinition(id)
+		}
+		return nil, fmt.Errorf("There is no bean with ID [%v]: %w", id, ErrBeanNotFound)
+	}
+
+	return &beanDefinition{
+		id:           id,
+		bean:         bean,
+		dependencies: ctx.dependencyGraph().dependenciesOf(bean),
+	}, nil
+}
+
+// IsSingletonInstantiated returns false for prototypes and for singletons
+// which haven't been created yet.
+func (ctx *applicationContext) IsSingletonInstantiated(id string) bool {
+
+	bean, present := ctx.beanById[id]
+
+	if !present {
+		if ctx.parent != nil {
+			return ctx.parent.IsSingletonInstantiated(id)
+		}
+		return false
+	}
+
+	ctx.lock.RLock()
+	defer ctx.lock.RUnlock()
+
+	_, instantiated := ctx.singletons[bean]
+	return instantiated
+}
+
+// GetBeanIDsForType returns sorted IDs of beans in this context which can
+// be assigned to the type of i, either as values or as pointers.
+func (ctx *applicationContext) GetBeanIDsForType(i interface{}) []string {
+
+	tvpe := typeOfBean(i)
+	ids := make([]string, 0)
+
+	for _, id := range ctx.GetBeanIDs() {
+		if isAutowireCandidate(tvpe, ctx.beanById[id].GetType()) {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids
+}
+
+// GetBeansOfType gets all beans returned by GetBeanIDsForType(i), keyed by
+// their IDs. Beans which aren't created yet are created. Request-scoped beans
+// and beans of registered scopes, e.g. pooled objects, are skipped, since
+// they need a request or have to be released.
+func (ctx *applicationContext) GetBeansOfType(i interface{}) (map[string]interface{}, error) {
+
+	beans := make(map[string]interface{})
+
+	for _, id := range ctx.GetBeanIDsForType(i) {
+		switch ctx.beanById[id].GetScope() {
+		case Default, Singleton, Prototype:
+		default:
+			continue
+		}
+		bean, e := ctx.GetBean(id)
+		if e != nil {
+			return nil, e
+		}
+		beans[id] = bean
+	}
+
+	return beans, nil
+}
+
+// Finalize finalizes registered scopes, e.g. pooled objects, and singletons
+// of this context only. Singletons of the parent context are left untouched.
+// All finalizers are called even if some fail, and the first error is
+// returned.
 func (ctx *applicationContext) Finalize() error {
-	cur := ctx.singletonList.Back()
-	for cur != nil {
+
+	// scoped beans may depend on singletons, so they are finalized first
+	first := ctx.finalizeScopes()
+	if e := ctx.finalizeSingletons(); first == nil {
+		first = e
+	}
+	return first
+}
+
+// finalizeSingletons finalizes singletons of this context in the reverse
+// order they are created. All of them are finalized even if some fail, and
+// the first error is returned.
+func (ctx *applicationContext) finalizeSingletons() error {
+
+	// finalizers are called without the lock, since they may get beans
+	ctx.lock.RLock()
+	beans := make([]BeanI, 0, ctx.singletonList.Len())
+	values := make([]reflect.Value, 0, ctx.singletonList.Len())
+	for cur := ctx.singletonList.Back(); cur != nil; cur = cur.Prev() {
 		bean := cur.Value.(BeanI)
-		value := ctx.singletons[bean]
-		if e := ctx.callFinalizeFunc(*value, bean); e != nil {
-			return fmt.Errorf(
-				"Can't call finalize function of bean [%v]. Caused by: [%v]",
+		beans = append(beans, bean)
+		values = append(values, *ctx.singletons[bean])
+	}
+	ctx.lock.RUnlock()
+
+	var first error
+	for i, bean := range beans {
+		if e := ctx.callFinalizeFunc(values[i], bean); e != nil && first == nil {
+			first = fmt.Errorf(
+				"Can't call finalize function of bean [%v]. Caused by: %w",
 				bean, e)
 		}
-		cur = cur.Prev()
 	}
-	return nil
+	return first
+}
+
+// finalizeScopes finalizes registered scopes which can be finalized, e.g.
+// TTLScopeI and PoolScopeI, in the order of their names. All of them are
+// finalized even if some fail, and the first error is returned.
+func (ctx *applicationContext) finalizeScopes() error {
+
+	names := make([]string, 0, len(ctx.scopes))
+	for name := range ctx.scopes {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+
+	var first error
+	for _, name := range names {
+		scope, ok := ctx.scopes[Scope(name)].(interface{ Finalize() error })
+		if !ok {
+			continue
+		}
+		if e := scope.Finalize(); e != nil && first == nil {
+			first = fmt.Errorf("Can't finalize scope [%v]. Caused by: %w", name, e)
+		}
+	}
+	return first
 }
 
 func (ctx *applicationContext) setRefBean(parent BeanI) error {
@@ -97,7 +484,7 @@ func (ctx *applicationContext) setRefBean(parent BeanI) error {
 	}
 
 	if sbean, ok := parent.(*structBean); ok {
-		for name, ps := range sbean.GetProperties() {
+		for name, ps := range ctx.getProperties(sbean) {
 			for _, p := range ps {
 				bs[p] = fmt.Sprintf("the field [%s]", name)
 			}
@@ -105,18 +492,32 @@ func (ctx *applicationContext) setRefBean(parent BeanI) error {
 	}
 
 	for bean, des := range bs {
+
+		if _, ok, e := ctx.getConfigString(bean); ok && e != nil {
+			return fmt.Errorf("Can't resolve %s of bean [%v]. Caused by: %w",
+				des, beanName(parent), e)
+		}
+
 		switch bean.(type) {
 		case StructBeanI:
 			if e := ctx.setRefBean(bean); e != nil {
-				return fmt.Errorf("Replace reference beans for %s inside bean [%v] failed. Caused by: %v",
+				return fmt.Errorf("Replace reference beans for %s inside bean [%v] failed. Caused by: %w",
 					des, bean, e)
 			}
 		case ReferenceBeanI:
 			if target, present := ctx.beanById[*bean.GetID()]; present {
 				bean.(ReferenceBeanI).SetReference(target)
+			} else if ctx.parent != nil && ctx.parent.ContainsBean(*bean.GetID()) {
+				bean.(ReferenceBeanI).SetReference(&parentBean{
+					id:     *bean.GetID(),
+					parent: ctx.parent,
+				})
+			} else if reason, present := ctx.disabledById[*bean.GetID()]; present {
+				return fmt.Errorf("ID [%v] of [%v] inside bean [%v] refers to a bean disabled by %v: %w",
+					*bean.GetID(), des, bean, reason, ErrBeanNotFound)
 			} else {
-				return fmt.Errorf("Can find ID [%v] of [%v] inside bean [%v]",
-					*bean.GetID(), des, bean)
+				return fmt.Errorf("Can find ID [%v] of [%v] inside bean [%v]: %w",
+					*bean.GetID(), des, bean, ErrBeanNotFound)
 			}
 		}
 	}
@@ -124,32 +525,97 @@ func (ctx *applicationContext) setRefBean(parent BeanI) error {
 	return nil
 }
 
+// lookupBean finds the definition of a bean from this context and its
+// ancestors.
+func (ctx *applicationContext) lookupBean(id string) (BeanI, bool) {
+	if bean, present := ctx.beanById[id]; present {
+		return bean, true
+	}
+	if p, ok := ctx.parent.(*applicationContext); ok {
+		return p.lookupBean(id)
+	}
+	return nil, false
+}
+
+// addBeans adds beans without conditions first, and then the conditional
+// ones in the order they are declared. A condition is evaluated against the
+// beans added before it, so a default bean with OnMissingBean(...) is
+// replaced by the one declared by users wherever it is declared. It returns
+// the added beans.
+func (ctx *applicationContext) addBeans(beans []BeanI) ([]BeanI, error) {
+
+	added := make([]BeanI, 0, len(beans))
+	conditionals := make([]*structBean, 0)
+
+	for _, bean := range beans {
+		if sbean, ok := bean.(*structBean); ok && len(sbean.GetConditions()) > 0 {
+			conditionals = append(conditionals, sbean)
+			continue
+		}
+		if e := ctx.addBean(bean); e != nil {
+			return nil, fmt.Errorf("Can't add bean [%v]. Cuased by: %w", bean, e)
+		}
+		added = append(added, bean)
+	}
+
+	for _, sbean := range conditionals {
+		matched, reason := ctx.matchConditions(sbean)
+		if !matched {
+			if id := sbean.GetID(); id != nil {
+				if _, present := ctx.beanById[*id]; !present {
+					ctx.disabledById[*id] = reason
+				}
+			}
+			continue
+		}
+		if e := ctx.addBean(sbean); e != nil {
+			return nil, fmt.Errorf("Can't add bean [%v]. Cuased by: %w", sbean, e)
+		}
+		if id := sbean.GetID(); id != nil {
+			delete(ctx.disabledById, *id)
+		}
+		added = append(added, sbean)
+	}
+
+	return added, nil
+}
+
 func (ctx *applicationContext) addBean(bean BeanI) error {
 
 	if _, ok := bean.(ReferenceBeanI); ok {
 		return nil
 	}
 
+	if _, ok := bean.(PropertyBeanI); ok {
+		return nil
+	}
+
 	if e := ctx.addBeanById(bean); e != nil {
 		return e
 	}
 
 	if e := ctx.checkType(bean); e != nil {
-		return fmt.Errorf("Type is invalid. Caused by: %v", e)
+		return fmt.Errorf("Type is invalid. Caused by: %w", e)
 	}
 
 	if e := ctx.checkFactory(bean); e != nil {
-		return fmt.Errorf("Factory is invalid. Caused by: %v", e)
+		return fmt.Errorf("Factory is invalid. Caused by: %w", e)
 	}
 
 	if e := ctx.checkScope(bean); e != nil {
-		return fmt.Errorf("Scope is invalid. Caused by: %v", e)
+		return fmt.Errorf("Scope is invalid. Caused by: %w", e)
 	}
 
 	for _, ps := range bean.GetProperties() {
 		for _, p := range ps {
+			if sbean, ok := p.(*structBean); ok && len(sbean.GetProfiles()) > 0 {
+				return fmt.Errorf("Bean [%v] inside a property can't have profiles", p)
+			}
+			if sbean, ok := p.(*structBean); ok && len(sbean.GetConditions()) > 0 {
+				return fmt.Errorf("Bean [%v] inside a property can't have conditions", p)
+			}
 			if e := ctx.addBean(p); e != nil {
-				return fmt.Errorf("Can't add property bean [%v]. Cuased by: %v", p, e)
+				return fmt.Errorf("Can't add property bean [%v]. Cuased by: %w", p, e)
 			}
 		}
 	}
@@ -157,46 +623,36 @@ func (ctx *applicationContext) addBean(bean BeanI) error {
 	return nil
 }
 
-func (ctx *applicationContext) checkDependencyLoop(bean BeanI) error {
-	for _, ps := range bean.GetProperties() {
-		for _, p := range ps {
-
-			ctx.parentByChild[p] = bean
+// checkDependencyLoop finds a cycle in dependencies through fields and
+// factory arguments.
+func (ctx *applicationContext) checkDependencyLoop() error {
 
-			parent := bean
-			for parent.GetID() == nil {
-				var present bool
-				if parent, present = ctx.parentByChild[parent]; present {
-					continue
-				} else {
-					break
-				}
-			}
+	g := ctx.dependencyGraph()
 
-			var parentID *string
-			if parent != nil {
-				parentID = parent.GetID()
-			}
-			childID := p.GetID()
-
-			if parentID != nil && childID != nil {
-				if !ctx.graph.AddDependency(*childID, *parentID) {
-					return fmt.Errorf("Found a circle dependency from [%v] tp [%v]", *parentID, *childID)
-				}
-			}
+	var cycle []string
+	if ctx.allowCircular {
+		// singletons can be injected into properties of each other
+		cycle = g.findCycleExcept(func(edge *dependencyEdge) bool {
+			return !edge.argument && isSingleton(edge.from.scope) && isSingleton(edge.to.scope)
+		})
+	} else {
+		cycle = g.findCycle()
+	}
 
-			if e := ctx.checkDependencyLoop(p); e != nil {
-				return fmt.Errorf("Detected dependency loop. Cuased by: %v", e)
-			}
-		}
+	if cycle != nil {
+		return &CircularDependencyError{Path: cycle}
 	}
 	return nil
 }
 
+func isSingleton(scope Scope) bool {
+	return scope == Default || scope == Singleton
+}
+
 func (ctx *applicationContext) addBeanById(bean BeanI) error {
 	if id := bean.GetID(); id != nil {
 		if _, present := ctx.beanById[*id]; present {
-			return fmt.Errorf("ID [%v] already exist", *id)
+			return &DuplicateIDError{ID: *id}
 		}
 		ctx.beanById[*id] = bean
 	}
@@ -220,8 +676,11 @@ func (ctx *applicationContext) checkScope(bean BeanI) error {
 		if bean.GetFinalize() != nil {
 			return fmt.Errorf("A prototype bean can't have finalizer. ")
 		}
+	case Request:
 	default:
-		return fmt.Errorf("Unkown scope [%v]", bean.GetScope())
+		if _, present := ctx.scopes[bean.GetScope()]; !present {
+			return fmt.Errorf("Unkown scope [%v]", bean.GetScope())
+		}
 	}
 	return nil
 }
@@ -274,104 +733,242 @@ func (ctx *applicationContext) checkFactory(bean BeanI) error {
 	return nil
 }
 
-func (ctx *applicationContext) getBean(bean BeanI) (*reflect.Value, error) {
+// getBean gets the instance of the bean. path is the resolution path from
+// the bean asked by users to the bean, which is reported in errors.
+func (ctx *applicationContext) getBean(bean BeanI, path resolutionPath, c *creation) (*reflect.Value, error) {
 
 	if r, ok := bean.(ReferenceBeanI); ok {
-		return ctx.getBean(r.GetReference())
+		return ctx.getBean(r.GetReference(), path, c)
+	}
+
+	if p, ok := bean.(*parentBean); ok {
+		if c != nil && c.context != nil {
+			return p.getValue(c.context)
+		}
+		return p.GetValue()
 	}
 
 	switch bean.GetScope() {
 	case Singleton:
-		return ctx.getSingletonBean(bean)
+		return ctx.getSingletonBean(bean, path, c)
 	case Prototype:
-		return ctx.getPrototypeBean(bean)
+		return ctx.getPrototypeBean(bean, path, c)
 	case Default:
-		return ctx.getSingletonBean(bean)
+		return ctx.getSingletonBean(bean, path, c)
+	case Request:
+		return ctx.getRequestBean(bean, path, c)
 	default:
-		return nil, fmt.Errorf("Scope [%T] of bean [%v] is not support", bean.GetScope(), bean)
+		return ctx.getScopedBean(bean, scopedName(bean), ctx.scopes[bean.GetScope()], path, c)
 	}
 }
 
-func (ctx *applicationContext) getSingletonBean(bean BeanI) (*reflect.Value, error) {
+func (ctx *applicationContext) getSingletonBean(bean BeanI, path resolutionPath, c *creation) (*reflect.Value, error) {
+
+	ctx.lock.RLock()
+	value, present := ctx.singletons[bean]
+	ctx.lock.RUnlock()
+
+	if present {
+		return value, nil
+	}
+
+	if ctx.allowCircular {
+		return ctx.getCircularSingletonBean(bean, path, c)
+	}
 
-	if value, present := ctx.singletons[bean]; present {
+	// Only one goroutine creates the bean. Others wait for it and then
+	// take the created one. There is no dead lock since dependency loops
+	// are rejected by NewApplicationContext.
+	//
+	// The creation state isn't passed on, since singletons never belong to
+	// a request.
+	beanLock := ctx.getSingletonLock(bean)
+	beanLock.Lock()
+	defer beanLock.Unlock()
+
+	ctx.lock.RLock()
+	value, present = ctx.singletons[bean]
+	ctx.lock.RUnlock()
+
+	if present {
 		return value, nil
 	}
 
-	value, e := ctx.getPrototypeBean(bean)
+	value, e := ctx.getPrototypeBean(bean, path, nil)
 
 	if e != nil {
 		return nil, e
 	}
 
+	ctx.lock.Lock()
 	ctx.singletons[bean] = value
 	ctx.singletonList.PushBack(bean)
+	ctx.lock.Unlock()
 
 	return value, nil
 }
-func (ctx *applicationContext) getPrototypeBean(bean BeanI) (*reflect.Value, error) {
+
+func (ctx *applicationContext) getSingletonLock(bean BeanI) *sync.Mutex {
+	ctx.lock.Lock()
+	defer ctx.lock.Unlock()
+
+	beanLock, present := ctx.singletonLocks[bean]
+	if !present {
+		beanLock = &sync.Mutex{}
+		ctx.singletonLocks[bean] = beanLock
+	}
+	return beanLock
+}
+func (ctx *applicationContext) getPrototypeBean(bean BeanI, path resolutionPath, c *creation) (*reflect.Value, error) {
+
+	value, e := ctx.instantiateBean(bean, path, c)
+	if e != nil {
+		return nil, e
+	}
+
+	if e := ctx.populateBean(bean, value, path, c); e != nil {
+		return nil, e
+	}
+
+	if e := ctx.initializeBean(bean, value, path); e != nil {
+		return nil, e
+	}
+
+	return value, nil
+}
+
+// instantiateBean creates the instance of the bean by its factory.
+func (ctx *applicationContext) instantiateBean(bean BeanI, path resolutionPath, c *creation) (*reflect.Value, error) {
+
+	step := resolutionStep(bean)
 
 	factory, factoryArgvBeans := bean.GetFactory()
 	factoryV := reflect.ValueOf(factory)
 
-	var value *reflect.Value
-	var e error
-	if value, e = ctx.createBeanByFactory(factoryV, factoryArgvBeans); e != nil {
-		return nil, fmt.Errorf("Create bean failed. Cuased by: %v", e)
+	value, e := ctx.createBeanByFactory(factoryV, factoryArgvBeans, path, step, c)
+	if e != nil {
+		return nil, newBeanCreationError(bean, "", path.with(step), e)
 	}
 
-	for name, ps := range bean.GetProperties() {
+	return value, nil
+}
 
-		field := value.Elem().FieldByName(name)
+// populateBean injects properties into the instance of the bean.
+func (ctx *applicationContext) populateBean(bean BeanI, value *reflect.Value, path resolutionPath, c *creation) error {
 
-		switch field.Type().Kind() {
-		case reflect.Slice:
-			if e := ctx.injectSlice(field, ps...); e != nil {
-				return nil, fmt.Errorf("Can't inject field [%v] into bean [%v]. Caused by: %v", name, bean, e)
-			}
-		case reflect.Map:
-			return nil, fmt.Errorf("TODO")
-		default:
-			if e := ctx.inject(field, ps[0]); e != nil {
-				return nil, fmt.Errorf("Can't inject field [%v] into bean [%v]. Caused by: %v", name, bean, e)
-			}
+	step := resolutionStep(bean)
+
+	// a path like "Server.Port" is injected after "Server", which would
+	// overwrite it otherwise
+	properties := ctx.getProperties(bean)
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+
+		ps := properties[name]
+		fieldPath := path.with(step + "." + name)
+
+		var entries []EntryI
+		if sbean, ok := bean.(*structBean); ok {
+			entries = sbean.GetEntries(name)
+		}
+
+		property, e := newPropertyPath(value.Type(), name)
+		if e != nil {
+			return newBeanCreationError(bean, name, fieldPath, e)
 		}
 
+		e = property.inject(*value, func(field reflect.Value) error {
+			return ctx.injectProperty(field, fieldPath, c, entries, ps...)
+		})
+		if e != nil {
+			return newBeanCreationError(bean, name, fieldPath, e)
+		}
 	}
 
+	return nil
+}
+
+// initializeBean calls the initializer of the bean.
+func (ctx *applicationContext) initializeBean(bean BeanI, value *reflect.Value, path resolutionPath) error {
 	if e := ctx.callInitFunc(*value, bean); e != nil {
-		return nil, fmt.Errorf("Can't call initial function of bean [%v]. Caused by: [%v]", bean, e)
+		return newBeanCreationError(bean, "", path.with(resolutionStep(bean)),
+			fmt.Errorf("Can't call initial function. Caused by: %w", e))
 	}
+	return nil
+}
 
-	return value, nil
+// injectProperty injects values of a property into the field, which is a
+// field of the bean or the argument of a setter.
+func (ctx *applicationContext) injectProperty(field reflect.Value, path resolutionPath, c *creation, entries []EntryI, beans ...BeanI) error {
+
+	switch field.Type().Kind() {
+	case reflect.Slice:
+		if entries != nil {
+			return fmt.Errorf("Entries can't be injected into a slice field")
+		}
+		return ctx.injectSlice(field, path, c, beans...)
+	case reflect.Map:
+		if entries == nil {
+			// a map bean, e.g. a literal map or a reference to a map
+			return ctx.inject(field, path, c, beans[0])
+		}
+		return ctx.injectMap(field, path, c, entries, beans...)
+	default:
+		if entries != nil {
+			return fmt.Errorf("Entries can't be injected into a non-map field")
+		}
+		return ctx.inject(field, path, c, beans[0])
+	}
 }
 
-func (ctx *applicationContext) createBeanByFactory(fn reflect.Value, argvs []BeanI) (*reflect.Value, error) {
+// createBeanByFactory calls the factory of the bean, whose resolution step
+// is step. Errors of arguments are resolutionError or errors of beans they
+// depend on.
+func (ctx *applicationContext) createBeanByFactory(fn reflect.Value, argvs []BeanI, path resolutionPath, step string, c *creation) (*reflect.Value, error) {
 
 	values := make([]reflect.Value, len(argvs))
 
 	for i, argv := range argvs {
-		value, e := ctx.getBean(argv)
+
+		argPath := path.with(fmt.Sprintf("%s.factory arg #%d", step, i))
+		argFailed := func(e error) error {
+			return &resolutionError{path: argPath.with(resolutionStep(argv)), cause: e}
+		}
+
+		if value, ok, e := ctx.getConfigValue(argv, fn.Type().In(i)); ok {
+			if e != nil {
+				return nil, argFailed(e)
+			}
+			values[i] = value
+			continue
+		}
+
+		value, e := ctx.getBean(argv, argPath, c)
 		if e != nil {
-			return nil, fmt.Errorf("Can't get the [%d] argument from bean [%v]. Caused by: %v", i, argv, e)
+			return nil, e
 		}
 
 		fromType := value.Type()
 		toType := fn.Type().In(i)
 
-		if fromType.ConvertibleTo(toType) {
-			values[i] = *value
-		} else if fromType.Elem().ConvertibleTo(toType) {
+		if v, ok := convertValue(*value, toType); ok {
+			values[i] = v
+		} else if v, ok := convertElem(*value, toType); ok {
 			if Singleton == argv.GetScope() {
-				return nil, fmt.Errorf("Can't inject a singleton to the [%d] non-pointer argument. ", i)
+				return nil, argFailed(fmt.Errorf("Can't inject a singleton to the [%d] non-pointer argument. ", i))
 			}
-			values[i] = value.Elem()
+			values[i] = v
 		} else {
-			return nil, fmt.Errorf("The type of the [%d] argument isn't [%v] nor [%v]",
+			return nil, argFailed(fmt.Errorf("The type of the [%d] argument isn't [%v] nor [%v]",
 				i,
 				fromType,
 				fromType.Elem(),
-			)
+			))
 		}
 	}
 
@@ -391,16 +988,16 @@ func (ctx *applicationContext) createBeanByFactory(fn reflect.Value, argvs []Bea
 	case 1:
 		value = &returns[0]
 		if e, ok := value.Interface().(error); ok {
-			return nil, fmt.Errorf("Get error from factory. Caused by: %v", e)
+			return nil, fmt.Errorf("Get error from factory. Caused by: %w", e)
 		}
 	default:
 		value = &returns[0]
 		if e, ok := value.Interface().(error); ok {
-			return nil, fmt.Errorf("Get error from factory. Caused by: %v", e)
+			return nil, fmt.Errorf("Get error from factory. Caused by: %w", e)
 		}
 		if returns[1].IsValid() {
 			if e, ok := returns[1].Interface().(error); ok {
-				return nil, fmt.Errorf("Get error from factory. Caused by: %v", e)
+				return nil, fmt.Errorf("Get error from factory. Caused by: %w", e)
 			}
 		}
 	}
@@ -408,66 +1005,154 @@ func (ctx *applicationContext) createBeanByFactory(fn reflect.Value, argvs []Bea
 	return value, nil
 }
 
-func (ctx *applicationContext) inject(field reflect.Value, bean BeanI) error {
+// inject injects the bean into the field. path is the resolution path to
+// the field.
+func (ctx *applicationContext) inject(field reflect.Value, path resolutionPath, c *creation, bean BeanI) error {
 
-	pv, e := ctx.getBean(bean)
+	failed := func(e error) error {
+		return &resolutionError{path: path.with(resolutionStep(bean)), cause: e}
+	}
+
+	if value, ok, e := ctx.getConfigValue(bean, field.Type()); ok {
+		if e != nil {
+			return failed(e)
+		}
+		field.Set(value)
+		return nil
+	}
+
+	pv, e := ctx.getBean(bean, path, c)
 	if e != nil {
-		return fmt.Errorf("Can't get bean [%v]. Caused by: %v", bean, e)
+		return e
 	}
 
 	fromType := pv.Type()
 	toType := field.Type()
 
-	if fromType.ConvertibleTo(toType) {
-		field.Set(*pv)
-	} else if fromType.Elem().ConvertibleTo(toType) {
+	if value, ok := convertValue(*pv, toType); ok {
+		field.Set(value)
+	} else if value, ok := convertElem(*pv, toType); ok {
 		if Singleton == bean.GetScope() {
-			return fmt.Errorf("Can't inject a singleton to non-pointer filed")
+			return failed(fmt.Errorf("Can't inject a singleton to non-pointer filed"))
 		}
-		field.Set(pv.Elem())
+		field.Set(value)
 	} else {
-		return fmt.Errorf("Bean [%v] can't be convert to [%v]",
-			bean,
+		return failed(fmt.Errorf("Bean [%v] can't be convert to [%v]",
+			fromType,
 			toType,
-		)
+		))
 	}
 
 	return nil
 }
 
-func (ctx *applicationContext) injectSlice(field reflect.Value, beans ...BeanI) error {
+func (ctx *applicationContext) injectSlice(field reflect.Value, path resolutionPath, c *creation, beans ...BeanI) error {
 
 	slice := reflect.MakeSlice(field.Type(), len(beans), len(beans))
 
 	for i, bean := range beans {
-		pv, e := ctx.getBean(bean)
-		if e != nil {
-			return fmt.Errorf("Can't get bean [%v]. Caused by: %v", bean, e)
+
+		if e := ctx.inject(slice.Index(i), path.index(i), c, bean); e != nil {
+			return e
+		}
+	}
+
+	field.Set(slice)
+
+	return nil
+}
+
+func (ctx *applicationContext) injectMap(field reflect.Value, path resolutionPath, c *creation, entries []EntryI, beans ...BeanI) error {
+
+	m := reflect.MakeMapWithSize(field.Type(), len(beans))
+
+	for i, bean := range beans {
+
+		if entries[i] == nil {
+			return &resolutionError{path: path.index(i), cause: fmt.Errorf("The [%d] value isn't an entry", i)}
 		}
 
-		fromType := pv.Type()
-		toType := field.Type().Elem()
+		keyType := field.Type().Key()
+		key := reflect.ValueOf(entries[i].GetKey())
+		if !key.IsValid() {
+			return &resolutionError{path: path.index(i), cause: fmt.Errorf("The key of the [%d] entry is nil", i)}
+		}
+		converted, ok := convertValue(key, keyType)
+		if !ok {
+			return &resolutionError{path: path.index(key.Interface()),
+				cause: fmt.Errorf("Key [%v] can't be convert to [%v]", key.Interface(), keyType)}
+		}
+		key = converted
 
-		if fromType.ConvertibleTo(toType) {
-			slice.Index(i).Set(*pv)
-		} else if fromType.Elem().ConvertibleTo(toType) {
-			if Singleton == bean.GetScope() {
-				return fmt.Errorf("Can't inject a singleton to non-pointer filed")
-			}
-			slice.Index(i).Set(pv.Elem())
-		} else {
-			return fmt.Errorf("Bean [%v] can't be convert to [%v]",
-				bean,
-				toType,
-			)
+		if m.MapIndex(key).IsValid() {
+			return &resolutionError{path: path.index(key.Interface()),
+				cause: fmt.Errorf("Key [%v] is duplicated", key.Interface())}
+		}
+
+		value := reflect.New(field.Type().Elem()).Elem()
+		if e := ctx.inject(value, path.index(key.Interface()), c, bean); e != nil {
+			return e
 		}
+		m.SetMapIndex(key, value)
 	}
 
-	field.Set(slice)
+	field.Set(m)
 
 	return nil
 }
 
+// getConfigString returns the string of a property bean or a string value
+// with placeholders. ok is false if the bean is neither of them.
+func (ctx *applicationContext) getConfigString(bean BeanI) (s string, ok bool, e error) {
+
+	switch b := bean.(type) {
+	case *propertyBean:
+		raw, present := ctx.environment.GetProperty(b.GetKey())
+		if !present {
+			return "", true, fmt.Errorf("Can't find property [%v]", b.GetKey())
+		}
+		if s, e = resolvePlaceholders(raw, ctx.environment); e != nil {
+			return "", true, fmt.Errorf("Can't resolve property [%v]. Caused by: %w", b.GetKey(), e)
+		}
+		return s, true, nil
+	case *valueBean:
+		raw, isString := b.value.(string)
+		if !isString || !hasPlaceholder(raw) {
+			return "", false, nil
+		}
+		if s, e = resolvePlaceholders(raw, ctx.environment); e != nil {
+			return "", true, fmt.Errorf("Can't resolve [%v]. Caused by: %w", raw, e)
+		}
+		return s, true, nil
+	default:
+		return "", false, nil
+	}
+}
+
+// getConfigValue is the same as getConfigString but converts the string
+// into toType.
+func (ctx *applicationContext) getConfigValue(bean BeanI, toType reflect.Type) (value reflect.Value, ok bool, e error) {
+
+	s, ok, e := ctx.getConfigString(bean)
+	if !ok || e != nil {
+		return reflect.Value{}, ok, e
+	}
+
+	if value, e = convertString(s, toType); e != nil {
+		return reflect.Value{}, true, fmt.Errorf("Can't convert [%v]. Caused by: %w", s, e)
+	}
+
+	return value, true, nil
+}
+
+// beanName returns the ID of the bean, or its type if the bean has no ID.
+func beanName(bean BeanI) string {
+	if id := bean.GetID(); id != nil {
+		return *id
+	}
+	return fmt.Sprintf("anonymous %v", bean.GetType())
+}
+
 func (ctx *applicationContext) callInitFunc(value reflect.Value, bean BeanI) error {
 
 	initName := bean.GetInit()
@@ -494,9 +1179,9 @@ func (ctx *applicationContext) callInitFunc(value reflect.Value, bean BeanI) err
 				return nil
 			} else {
 				return fmt.Errorf(
-					"Function [%v] return an error. Caused by: %v",
+					"Function [%v] return an error. Caused by: %w",
 					*initName,
-					rv[0].Interface(),
+					rv[0].Interface().(error),
 				)
 			}
 		} else {
@@ -542,9 +1227,9 @@ func (ctx *applicationContext) callFinalizeFunc(value reflect.Value, bean BeanI)
 				return nil
 			} else {
 				return fmt.Errorf(
-					"Function [%v] return an error. Caused by: %v",
+					"Function [%v] return an error. Caused by: %w",
 					*finalName,
-					rv[0].Interface(),
+					rv[0].Interface().(error),
 				)
 			}
 		} else {
@@ -1,11 +1,46 @@
 package gospring
 
+import "context"
+
 // ApplicationContextI is an interface to management beans.
+//
+// All functions are safe for concurrent use by multiple goroutines.
 type ApplicationContextI interface {
 
 	// Accuire a bean from its ID.
 	GetBean(id string) (interface{}, error)
 
+	// Accuire a bean from its ID. Beans in the Request scope are acquired
+	// from the request scope held by the context.
+	GetBeanContext(c context.Context, id string) (interface{}, error)
+
+	// Check whether there is a bean with the ID in this context or its
+	// parent.
+	ContainsBean(id string) bool
+
+	// Get sorted IDs of beans defined in this context.
+	GetBeanIDs() []string
+
+	// Get the definition of a bean from its ID.
+	GetBeanDefinition(id string) (BeanDefinitionI, error)
+
+	// Check whether a singleton bean is created already.
+	IsSingletonInstantiated(id string) bool
+
+	// Get sorted IDs of beans which can be assigned to the type of i. Use
+	// (*SomeInterface)(nil) or a reflect.Type for an interface.
+	GetBeanIDsForType(i interface{}) []string
+
+	// Get beans which can be assigned to the type of i, keyed by their IDs.
+	// Request-scoped beans and beans of registered scopes are skipped.
+	GetBeansOfType(i interface{}) (map[string]interface{}, error)
+
+	// Get the environment which holds property sources.
+	GetEnvironment() EnvironmentI
+
+	// Export the dependency graph of beans in the format.
+	ExportGraph(format GraphFormat) ([]byte, error)
+
 	// A destory function of this instance
 	Finalize() error
 }
@@ -1,9 +1,13 @@
 package gospring
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 	"unsafe"
 
 	"github.com/stretchr/testify/mock"
@@ -290,6 +294,78 @@ func Test_Finalize_error(t *testing.T) {
 	assert.NotNil(t, ef)
 }
 
+func Test_Finalize_allFinalizers(t *testing.T) {
+	// arrange
+	ctx, e := Context().
+		Scope("a", NewTTLScope(time.Minute)).
+		Scope("b", NewTTLScope(time.Minute)).
+		Build(Beans(
+			Bean(Test_Finalize_error_struct{}).ID("1").Scope("a"),
+			Bean(Test_Finalize_struct{}).ID("2").Scope("b"),
+			Bean(Test_Finalize_error_struct{}).ID("3"),
+			Bean(Test_Finalize_struct{}).ID("4"),
+		)...)
+	require.Nil(t, e)
+	var beans []interface{}
+	for _, id := range []string{"1", "2", "4", "3"} {
+		bean, e := ctx.GetBean(id)
+		require.Nil(t, e)
+		beans = append(beans, bean)
+	}
+	beans[1].(*Test_Finalize_struct).b = true
+	beans[2].(*Test_Finalize_struct).b = true
+
+	// action
+	ef := ctx.Finalize()
+
+	// assert
+	require.NotNil(t, ef)
+	assert.Contains(t, ef.Error(), "Can't finalize scope [a]")
+	assert.False(t, beans[1].(*Test_Finalize_struct).b)
+	assert.False(t, beans[2].(*Test_Finalize_struct).b)
+}
+
+var Test_Finalize_getBean_ctx ApplicationContextI
+
+type Test_Finalize_getBean_struct struct {
+	other interface{}
+}
+
+func (s *Test_Finalize_getBean_struct) Finalize() error {
+	other, e := Test_Finalize_getBean_ctx.GetBean("other")
+	s.other = other
+	return e
+}
+
+func Test_Finalize_getBean(t *testing.T) {
+	// arrange
+	beans := Beans(
+		Bean(Test_Finalize_getBean_struct{}).ID("id"),
+		Bean(Test_Finalize_struct{}).ID("other"),
+	)
+
+	ctx, e := NewApplicationContext(beans...)
+	require.Nil(t, e)
+	Test_Finalize_getBean_ctx = ctx
+	bean, e := ctx.GetBean("id")
+	require.Nil(t, e)
+
+	// action
+	done := make(chan error)
+	go func() {
+		done <- ctx.Finalize()
+	}()
+
+	// assert
+	select {
+	case ef := <-done:
+		require.Nil(t, ef)
+		assert.NotNil(t, bean.(*Test_Finalize_getBean_struct).other)
+	case <-time.After(time.Second):
+		assert.Fail(t, "a finalizer getting a bean is blocked")
+	}
+}
+
 func Test_setRefBean_withProperty(t *testing.T) {
 	// arrange
 	type beanStruct1 struct {
@@ -948,7 +1024,7 @@ func Test_GetBean_injectFail(t *testing.T) {
 		Bean(beanStruct{}).ID("1"),
 		Bean(beanStruct{}).ID("2").Property("I", Ref("1")),
 	)
-	ctx, e := NewApplicationContext(beans...)
+	ctx, e := (&contextBuilder{skipValidation: true}).Build(beans...)
 	require.Nil(t, e)
 
 	// action
@@ -968,7 +1044,7 @@ func Test_GetBean_injectSliceFail(t *testing.T) {
 		Bean(beanStruct{}).ID("1"),
 		Bean(beanStruct{}).ID("2").Property("I", Ref("1")),
 	)
-	ctx, e := NewApplicationContext(beans...)
+	ctx, e := (&contextBuilder{skipValidation: true}).Build(beans...)
 	require.Nil(t, e)
 
 	// action
@@ -1060,7 +1136,7 @@ func Test_createBeanByFactory_injectSingletonArgvAsElem(t *testing.T) {
 			Bean(beanStruct{}).Singleton(),
 		),
 	)
-	ctx, e := NewApplicationContext(beans...)
+	ctx, e := (&contextBuilder{skipValidation: true}).Build(beans...)
 	require.Nil(t, e)
 
 	// action
@@ -1082,7 +1158,7 @@ func Test_createBeanByFactory_convertArgvFailed(t *testing.T) {
 			Bean(beanStruct{}),
 		),
 	)
-	ctx, e := NewApplicationContext(beans...)
+	ctx, e := (&contextBuilder{skipValidation: true}).Build(beans...)
 	require.Nil(t, e)
 
 	// action
@@ -1190,7 +1266,7 @@ func Test_inject_convertFailed(t *testing.T) {
 			}),
 		),
 	)
-	ctx, e := NewApplicationContext(beans...)
+	ctx, e := (&contextBuilder{skipValidation: true}).Build(beans...)
 	require.Nil(t, e)
 
 	// action
@@ -1201,6 +1277,60 @@ func Test_inject_convertFailed(t *testing.T) {
 	assert.NotNil(t, e)
 }
 
+type Test_inject_convert_struct struct {
+	Name  string
+	Count int
+	Size  uint
+	Ratio float64
+}
+
+func Test_inject_lossyConversion(t *testing.T) {
+	// arrange
+	cases := []struct {
+		name  string
+		value interface{}
+	}{
+		{"Name", 65},
+		{"Count", 80.9},
+		{"Size", -1},
+	}
+
+	for _, c := range cases {
+		beans := Beans(
+			Bean(Test_inject_convert_struct{}).ID("1").Property(c.name, c.value),
+		)
+		ctx, e := (&contextBuilder{skipValidation: true}).Build(beans...)
+		require.Nil(t, e)
+
+		// action
+		bean, e := ctx.GetBean("1")
+
+		// assert
+		assert.Nil(t, bean, "%s %v", c.name, c.value)
+		var ce *BeanCreationError
+		assert.True(t, errors.As(e, &ce), "%s %v: %v", c.name, c.value, e)
+	}
+}
+
+func Test_inject_losslessConversion(t *testing.T) {
+	// arrange
+	beans := Beans(
+		Bean(Test_inject_convert_struct{}).ID("1").
+			Property("Count", int8(80)).
+			Property("Size", 1).
+			Property("Ratio", 2),
+	)
+	ctx, e := NewApplicationContext(beans...)
+	require.Nil(t, e)
+
+	// action
+	bean, e := ctx.GetBean("1")
+
+	// assert
+	require.Nil(t, e)
+	assert.Equal(t, &Test_inject_convert_struct{Count: 80, Size: 1, Ratio: 2}, bean)
+}
+
 func Test_inject_singletonToElem(t *testing.T) {
 	// arrange
 	type beanStruct1 struct {
@@ -1214,7 +1344,7 @@ func Test_inject_singletonToElem(t *testing.T) {
 				Singleton(),
 		),
 	)
-	ctx, e := NewApplicationContext(beans...)
+	ctx, e := (&contextBuilder{skipValidation: true}).Build(beans...)
 	require.Nil(t, e)
 
 	// action
@@ -1305,7 +1435,7 @@ func Test_injectSlice_singletonToElem(t *testing.T) {
 				Singleton(),
 		),
 	)
-	ctx, e := NewApplicationContext(beans...)
+	ctx, e := (&contextBuilder{skipValidation: true}).Build(beans...)
 	require.Nil(t, e)
 
 	// action
@@ -1385,13 +1515,60 @@ func Test_callInitFunc_returnNilError(t *testing.T) {
 	assert.Nil(t, e)
 }
 
+var Test_callInitFunc_errSentinel = errors.New("sentinel")
+
+type Test_callInitFunc_returnError_struct struct{}
+
+func (t *Test_callInitFunc_returnError_struct) Init() error {
+	return Test_callInitFunc_errSentinel
+}
+
+func Test_callInitFunc_returnError(t *testing.T) {
+	// arrange
+	beans := Beans(
+		Bean(Test_callInitFunc_returnError_struct{}).ID("1"),
+	)
+	ctx, e := NewApplicationContext(beans...)
+	require.Nil(t, e)
+
+	// action
+	bean, e := ctx.GetBean("1")
+
+	// assert
+	assert.Nil(t, bean)
+	assert.True(t, errors.Is(e, Test_callInitFunc_errSentinel), "%v", e)
+}
+
+type Test_callFinalizeFunc_returnError_struct struct{}
+
+func (t *Test_callFinalizeFunc_returnError_struct) Finalize() error {
+	return Test_callInitFunc_errSentinel
+}
+
+func Test_callFinalizeFunc_returnError(t *testing.T) {
+	// arrange
+	beans := Beans(
+		Bean(Test_callFinalizeFunc_returnError_struct{}).ID("1"),
+	)
+	ctx, e := NewApplicationContext(beans...)
+	require.Nil(t, e)
+	_, e = ctx.GetBean("1")
+	require.Nil(t, e)
+
+	// action
+	e = ctx.Finalize()
+
+	// assert
+	assert.True(t, errors.Is(e, Test_callInitFunc_errSentinel), "%v", e)
+}
+
 func Test_callInitFunc_cantFindMethod(t *testing.T) {
 	// arrange
 	type beanStruct struct{}
 	beans := Beans(
 		Bean(beanStruct{}).ID("1").Init("aaa"),
 	)
-	ctx, e := NewApplicationContext(beans...)
+	ctx, e := (&contextBuilder{skipValidation: true}).Build(beans...)
 	require.Nil(t, e)
 
 	// action
@@ -1413,7 +1590,7 @@ func Test_callInitFunc_returnNonError(t *testing.T) {
 	beans := Beans(
 		Bean(Test_callInitFunc_returnNonError_struct{}).ID("1"),
 	)
-	ctx, e := NewApplicationContext(beans...)
+	ctx, e := (&contextBuilder{skipValidation: true}).Build(beans...)
 	require.Nil(t, e)
 
 	// action
@@ -1435,7 +1612,7 @@ func Test_callInitFunc_returnMoreThanTwoValues(t *testing.T) {
 	beans := Beans(
 		Bean(Test_callInitFunc_returnMoreThanTwoValues_struct{}).ID("1"),
 	)
-	ctx, e := NewApplicationContext(beans...)
+	ctx, e := (&contextBuilder{skipValidation: true}).Build(beans...)
 	require.Nil(t, e)
 
 	// action
@@ -1452,7 +1629,7 @@ func Test_callFinalizeFunc_cantFindMethod(t *testing.T) {
 	beans := Beans(
 		Bean(beanStruct{}).ID("1").Finalize("aaa"),
 	)
-	ctx, e := NewApplicationContext(beans...)
+	ctx, e := (&contextBuilder{skipValidation: true}).Build(beans...)
 	require.Nil(t, e)
 	bean, e := ctx.GetBean("1")
 	require.NotNil(t, bean)
@@ -1519,7 +1696,7 @@ func Test_callFinalizeFunc_returnNonError(t *testing.T) {
 	beans := Beans(
 		Bean(Test_callFinalizeFunc_returnNonError_struct{}).ID("1"),
 	)
-	ctx, e := NewApplicationContext(beans...)
+	ctx, e := (&contextBuilder{skipValidation: true}).Build(beans...)
 	require.Nil(t, e)
 	bean, e := ctx.GetBean("1")
 	require.NotNil(t, bean)
@@ -1543,7 +1720,7 @@ func Test_callFinalizeFunc_returnMoreThanTwoValues(t *testing.T) {
 	beans := Beans(
 		Bean(Test_callFinalizeFunc_returnMoreThanTwoValues_struct{}).ID("1"),
 	)
-	ctx, e := NewApplicationContext(beans...)
+	ctx, e := (&contextBuilder{skipValidation: true}).Build(beans...)
 	require.Nil(t, e)
 	bean, e := ctx.GetBean("1")
 	require.NotNil(t, bean)
@@ -1630,3 +1807,345 @@ func Test_GetBean_withChannel(t *testing.T) {
 	my.C <- 456
 	assert.Equal(t, 456, <-my.C)
 }
+
+func Test_injectMap(t *testing.T) {
+	// arrange
+	type beanStruct1 struct {
+		Name string
+	}
+	type beanStruct2 struct {
+		M map[string]*beanStruct1
+	}
+	beans := Beans(
+		Bean(beanStruct2{}).ID("1").Property("M",
+			Entry("a", Ref("2")),
+			Entry("b", Bean(beanStruct1{}).Property("Name", "b")),
+		),
+		Bean(beanStruct1{}).ID("2").Property("Name", "a"),
+	)
+	ctx, e := NewApplicationContext(beans...)
+	require.Nil(t, e)
+
+	// action
+	bean, e := ctx.GetBean("1")
+
+	// assert
+	require.Nil(t, e)
+	m := bean.(*beanStruct2).M
+	assert.Len(t, m, 2)
+	assert.Equal(t, "a", m["a"].Name)
+	assert.Equal(t, "b", m["b"].Name)
+}
+
+func Test_injectMap_singletonIsShared(t *testing.T) {
+	// arrange
+	type beanStruct1 struct{}
+	type beanStruct2 struct {
+		M map[string]*beanStruct1
+	}
+	beans := Beans(
+		Bean(beanStruct2{}).ID("1").Property("M",
+			Entry("a", Ref("2")),
+			Entry("b", Ref("2")),
+		),
+		Bean(beanStruct1{}).ID("2").Singleton(),
+	)
+	ctx, e := NewApplicationContext(beans...)
+	require.Nil(t, e)
+
+	// action
+	bean, e := ctx.GetBean("1")
+	require.Nil(t, e)
+	singleton, e := ctx.GetBean("2")
+	require.Nil(t, e)
+
+	// assert
+	m := bean.(*beanStruct2).M
+	assert.True(t, m["a"] == singleton)
+	assert.True(t, m["b"] == singleton)
+}
+
+func Test_injectMap_literalValuesAndNonStringKeys(t *testing.T) {
+	// arrange
+	type beanStruct struct {
+		M map[int]string
+	}
+	beans := Beans(
+		Bean(beanStruct{}).ID("1").Property("M",
+			Entry(1, "one"),
+			Entry(2, "two"),
+		),
+	)
+	ctx, e := NewApplicationContext(beans...)
+	require.Nil(t, e)
+
+	// action
+	bean, e := ctx.GetBean("1")
+
+	// assert
+	require.Nil(t, e)
+	assert.Equal(t, map[int]string{1: "one", 2: "two"}, bean.(*beanStruct).M)
+}
+
+func Test_injectMap_literalMap(t *testing.T) {
+	// arrange
+	type beanStruct struct {
+		M map[string]int
+	}
+	beans := Beans(
+		Bean(beanStruct{}).ID("1").Property("M", map[string]int{"a": 1}),
+	)
+	ctx, e := NewApplicationContext(beans...)
+	require.Nil(t, e)
+
+	// action
+	bean, e := ctx.GetBean("1")
+
+	// assert
+	require.Nil(t, e)
+	assert.Equal(t, map[string]int{"a": 1}, bean.(*beanStruct).M)
+}
+
+func Test_injectMap_singletonToElem(t *testing.T) {
+	// arrange
+	type beanStruct1 struct{}
+	type beanStruct2 struct {
+		M map[string]beanStruct1
+	}
+	beans := Beans(
+		Bean(beanStruct2{}).ID("1").Property("M",
+			Entry("a", Bean(beanStruct1{}).Singleton()),
+		),
+	)
+	ctx, e := (&contextBuilder{skipValidation: true}).Build(beans...)
+	require.Nil(t, e)
+
+	// action
+	bean, e := ctx.GetBean("1")
+
+	// assert
+	assert.Nil(t, bean)
+	assert.NotNil(t, e)
+}
+
+func Test_injectMap_prototypeToElem(t *testing.T) {
+	// arrange
+	type beanStruct1 struct{}
+	type beanStruct2 struct {
+		M map[string]beanStruct1
+	}
+	beans := Beans(
+		Bean(beanStruct2{}).ID("1").Property("M",
+			Entry("a", Bean(beanStruct1{}).Prototype()),
+		),
+	)
+	ctx, e := NewApplicationContext(beans...)
+	require.Nil(t, e)
+
+	// action
+	bean, e := ctx.GetBean("1")
+
+	// assert
+	assert.NotNil(t, bean)
+	assert.Nil(t, e)
+}
+
+func Test_injectMap_keyConvertFailed(t *testing.T) {
+	// arrange
+	type beanStruct struct {
+		M map[int]string
+	}
+	beans := Beans(
+		Bean(beanStruct{}).ID("1").Property("M", Entry("a", "b")),
+	)
+	ctx, e := (&contextBuilder{skipValidation: true}).Build(beans...)
+	require.Nil(t, e)
+
+	// action
+	bean, e := ctx.GetBean("1")
+
+	// assert
+	assert.Nil(t, bean)
+	assert.NotNil(t, e)
+}
+
+func Test_injectMap_numberKeyToString(t *testing.T) {
+	// arrange
+	type beanStruct struct {
+		M map[string]string
+	}
+	beans := Beans(
+		Bean(beanStruct{}).ID("1").Property("M", Entry(65, "b")),
+	)
+	// action
+	ctx, e := NewApplicationContext(beans...)
+
+	// assert
+	assert.Nil(t, ctx)
+	var ve *ValidationError
+	require.True(t, errors.As(e, &ve))
+	assert.Contains(t, ve.Error(), "key [65] can't be converted to [string]")
+}
+
+func Test_injectMap_duplicatedKey(t *testing.T) {
+	// arrange
+	type beanStruct struct {
+		M map[string]string
+	}
+	beans := Beans(
+		Bean(beanStruct{}).ID("1").Property("M",
+			Entry("a", "b"),
+			Entry("a", "c"),
+		),
+	)
+	ctx, e := (&contextBuilder{skipValidation: true}).Build(beans...)
+	require.Nil(t, e)
+
+	// action
+	bean, e := ctx.GetBean("1")
+
+	// assert
+	assert.Nil(t, bean)
+	assert.NotNil(t, e)
+}
+
+func Test_injectMap_mixedWithNonEntry(t *testing.T) {
+	// arrange
+	type beanStruct struct {
+		M map[string]string
+	}
+	beans := Beans(
+		Bean(beanStruct{}).ID("1").Property("M",
+			Entry("a", "b"),
+			"c",
+		),
+	)
+	ctx, e := (&contextBuilder{skipValidation: true}).Build(beans...)
+	require.Nil(t, e)
+
+	// action
+	bean, e := ctx.GetBean("1")
+
+	// assert
+	assert.Nil(t, bean)
+	assert.NotNil(t, e)
+}
+
+func Test_injectMap_entryToNonMapField(t *testing.T) {
+	// arrange
+	type beanStruct struct {
+		S []string
+	}
+	beans := Beans(
+		Bean(beanStruct{}).ID("1").Property("S", Entry("a", "b")),
+	)
+	ctx, e := (&contextBuilder{skipValidation: true}).Build(beans...)
+	require.Nil(t, e)
+
+	// action
+	bean, e := ctx.GetBean("1")
+
+	// assert
+	assert.Nil(t, bean)
+	assert.NotNil(t, e)
+}
+
+var Test_GetBean_concurrentSingleton_count int32
+
+type Test_GetBean_concurrentSingleton_struct1 struct {
+	B *Test_GetBean_concurrentSingleton_struct2
+}
+
+type Test_GetBean_concurrentSingleton_struct2 struct{}
+
+func (s *Test_GetBean_concurrentSingleton_struct2) Init() {
+	atomic.AddInt32(&Test_GetBean_concurrentSingleton_count, 1)
+	// make the creation slow enough for other goroutines to race with it
+	time.Sleep(10 * time.Millisecond)
+}
+
+func Test_GetBean_concurrentSingleton(t *testing.T) {
+	// arrange
+	beans := Beans(
+		Bean(Test_GetBean_concurrentSingleton_struct1{}).
+			ID("1").
+			Prototype().
+			Property("B", Ref("2")),
+		Bean(Test_GetBean_concurrentSingleton_struct2{}).
+			ID("2").
+			Singleton(),
+	)
+	ctx, e := NewApplicationContext(beans...)
+	require.Nil(t, e)
+
+	// action
+	n := 20
+	results := make([]interface{}, n)
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if i%2 == 0 {
+				results[i], errs[i] = ctx.GetBean("2")
+			} else {
+				var b interface{}
+				b, errs[i] = ctx.GetBean("1")
+				if errs[i] == nil {
+					results[i] = b.(*Test_GetBean_concurrentSingleton_struct1).B
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	// assert
+	assert.Equal(t, int32(1), atomic.LoadInt32(&Test_GetBean_concurrentSingleton_count))
+	for i := 0; i < n; i++ {
+		require.Nil(t, errs[i])
+		assert.True(t, results[0] == results[i])
+	}
+	assert.Nil(t, ctx.Finalize())
+}
+
+func Test_checkDependencyLoop_path(t *testing.T) {
+	// arrange
+	type beanStruct struct {
+		B interface{}
+	}
+	beans := Beans(
+		Bean(beanStruct{}).ID("1").Property("B",
+			Bean(beanStruct{}).Property("B", Ref("2")),
+		),
+		Bean(beanStruct{}).ID("2").Property("B", Ref("1")),
+	)
+
+	// action
+	_, e := NewApplicationContext(beans...)
+
+	// assert
+	require.NotNil(t, e)
+	assert.Contains(t, e.Error(), "[1 -> B -> anonymous#1 -> B -> 2 -> B -> 1]")
+}
+
+func Test_checkDependencyLoop_factoryArgument(t *testing.T) {
+	// arrange
+	type beanStruct struct {
+		B interface{}
+	}
+	fn := func(b interface{}) *beanStruct {
+		return &beanStruct{B: b}
+	}
+	beans := Beans(
+		Bean(beanStruct{}).ID("1").Property("B", Ref("2")),
+		Bean(beanStruct{}).ID("2").Factory(fn, Ref("1")),
+	)
+
+	// action
+	_, e := NewApplicationContext(beans...)
+
+	// assert
+	require.NotNil(t, e)
+	assert.Contains(t, e.Error(), "[1 -> B -> 2 -> factory arg #0 -> 1]")
+}
@@ -0,0 +1,167 @@
+package gospring
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const (
+	// AutowireTag is the key of struct tags to mark fields which should be
+	// injected automatically.
+	//
+	// type Astruct struct {
+	//     B *Bstruct `gs:"autowire"`
+	//     C *Cstruct `gs:"autowire,id=c_id"`
+	// }
+	AutowireTag string = "gs"
+
+	autowireFlag  string = "autowire"
+	autowireIDKey string = "id="
+)
+
+// autowire resolves a reference for each field tagged by AutowireTag of the
+// bean and beans inside it. References are kept by the context instead of
+// the bean, which may be built into other contexts where other beans are
+// chosen. A field which already has a property is skipped.
+func (ctx *applicationContext) autowire(bean BeanI) error {
+
+	sbean, ok := bean.(*structBean)
+	if !ok {
+		return nil
+	}
+
+	if tvpe := sbean.GetType(); tvpe != nil && tvpe.Kind() == reflect.Struct {
+		for i := 0; i < tvpe.NumField(); i++ {
+			field := tvpe.Field(i)
+
+			wired, id, e := parseAutowireTag(field.Tag.Get(AutowireTag))
+			if e != nil {
+				return fmt.Errorf("Invalid tag of field [%v]. Caused by: %w", field.Name, e)
+			}
+			if !wired {
+				continue
+			}
+			if _, present := sbean.properties[field.Name]; present {
+				continue
+			}
+			if field.PkgPath != "" {
+				return fmt.Errorf("Can't autowire unexported field [%v]", field.Name)
+			}
+
+			if id == "" {
+				if id, e = ctx.findAutowireCandidate(sbean, field.Type); e != nil {
+					return fmt.Errorf("Can't autowire field [%v]. Caused by: %w", field.Name, e)
+				}
+			}
+
+			if ctx.autowired[sbean] == nil {
+				ctx.autowired[sbean] = make(map[string]BeanI)
+			}
+			ctx.autowired[sbean][field.Name] = Ref(id).(BeanI)
+		}
+	}
+
+	_, argvs := sbean.GetFactory()
+	for i, argv := range argvs {
+		if e := ctx.autowire(argv); e != nil {
+			return fmt.Errorf("Can't autowire the number [%d] argument of factory function. Caused by: %w", i, e)
+		}
+	}
+
+	for name, ps := range sbean.GetProperties() {
+		for _, p := range ps {
+			if e := ctx.autowire(p); e != nil {
+				return fmt.Errorf("Can't autowire the bean inside field [%v]. Caused by: %w", name, e)
+			}
+		}
+	}
+
+	return nil
+}
+
+// getProperties returns properties of the bean with ones autowired by the
+// context.
+func (ctx *applicationContext) getProperties(bean BeanI) map[string][]BeanI {
+
+	wired := ctx.autowired[bean]
+	if len(wired) == 0 {
+		return bean.GetProperties()
+	}
+
+	properties := make(map[string][]BeanI, len(bean.GetProperties())+len(wired))
+	for name, ps := range bean.GetProperties() {
+		properties[name] = ps
+	}
+	for name, p := range wired {
+		properties[name] = []BeanI{p}
+	}
+	return properties
+}
+
+// findAutowireCandidate returns the ID of the only bean which can be
+// injected into a field with type fieldType.
+func (ctx *applicationContext) findAutowireCandidate(self BeanI, fieldType reflect.Type) (string, error) {
+
+	candidates := make([]string, 0)
+
+	for id, bean := range ctx.beanById {
+		if bean == self {
+			continue
+		}
+		if isAutowireCandidate(fieldType, bean.GetType()) {
+			candidates = append(candidates, id)
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+		if p, ok := ctx.parent.(*applicationContext); ok {
+			return p.findAutowireCandidate(nil, fieldType)
+		}
+		return "", fmt.Errorf("There is no bean with type [%v]", fieldType)
+	case 1:
+		return candidates[0], nil
+	default:
+		sort.Strings(candidates)
+		return "", fmt.Errorf("There are [%d] beans with type [%v]: %v",
+			len(candidates), fieldType, candidates)
+	}
+}
+
+func isAutowireCandidate(fieldType, beanType reflect.Type) bool {
+	if beanType == nil {
+		return false
+	}
+	return beanType.AssignableTo(fieldType) ||
+		reflect.PtrTo(beanType).AssignableTo(fieldType)
+}
+
+// parseAutowireTag parses tags like "autowire" or "autowire,id=b_id"
+func parseAutowireTag(tag string) (wired bool, id string, e error) {
+
+	if tag == "" {
+		return false, "", nil
+	}
+
+	parts := strings.Split(tag, ",")
+	if strings.TrimSpace(parts[0]) != autowireFlag {
+		return false, "", nil
+	}
+
+	for _, part := range parts[1:] {
+		part = strings.TrimSpace(part)
+		switch {
+		case strings.HasPrefix(part, autowireIDKey):
+			id = strings.TrimPrefix(part, autowireIDKey)
+			if id == "" {
+				return false, "", fmt.Errorf("ID is empty in tag [%v]", tag)
+			}
+		default:
+			return false, "", fmt.Errorf("Unknown option [%v] in tag [%v]", part, tag)
+		}
+	}
+
+	return true, id, nil
+}
@@ -0,0 +1,261 @@
+package gospring
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Test_autowire_interface interface {
+	Name() string
+}
+
+type Test_autowire_impl struct {
+	N string
+}
+
+func (s *Test_autowire_impl) Name() string {
+	return s.N
+}
+
+func Test_autowire_byType(t *testing.T) {
+	// arrange
+	type beanStruct1 struct {
+		Name string
+	}
+	type beanStruct2 struct {
+		B *beanStruct1 `gs:"autowire"`
+	}
+	beans := Beans(
+		Bean(beanStruct2{}).ID("2"),
+		Bean(beanStruct1{}).ID("1").Property("Name", "b"),
+	)
+	ctx, e := NewApplicationContext(beans...)
+	require.Nil(t, e)
+
+	// action
+	bean, e := ctx.GetBean("2")
+
+	// assert
+	require.Nil(t, e)
+	assert.Equal(t, "b", bean.(*beanStruct2).B.Name)
+}
+
+func Test_autowire_byInterface(t *testing.T) {
+	// arrange
+	type beanStruct struct {
+		I Test_autowire_interface `gs:"autowire"`
+	}
+	beans := Beans(
+		Bean(beanStruct{}).ID("1"),
+		Bean(Test_autowire_impl{}).ID("2").Property("N", "impl"),
+	)
+	ctx, e := NewApplicationContext(beans...)
+	require.Nil(t, e)
+
+	// action
+	bean, e := ctx.GetBean("1")
+
+	// assert
+	require.Nil(t, e)
+	assert.Equal(t, "impl", bean.(*beanStruct).I.Name())
+}
+
+func Test_autowire_byID(t *testing.T) {
+	// arrange
+	type beanStruct1 struct {
+		Name string
+	}
+	type beanStruct2 struct {
+		B *beanStruct1 `gs:"autowire,id=b"`
+	}
+	beans := Beans(
+		Bean(beanStruct2{}).ID("2"),
+		Bean(beanStruct1{}).ID("a").Property("Name", "a"),
+		Bean(beanStruct1{}).ID("b").Property("Name", "b"),
+	)
+	ctx, e := NewApplicationContext(beans...)
+	require.Nil(t, e)
+
+	// action
+	bean, e := ctx.GetBean("2")
+
+	// assert
+	require.Nil(t, e)
+	assert.Equal(t, "b", bean.(*beanStruct2).B.Name)
+}
+
+func Test_autowire_explicitPropertyWins(t *testing.T) {
+	// arrange
+	type beanStruct1 struct {
+		Name string
+	}
+	type beanStruct2 struct {
+		B *beanStruct1 `gs:"autowire"`
+	}
+	beans := Beans(
+		Bean(beanStruct2{}).ID("2").Property("B", Ref("b")),
+		Bean(beanStruct1{}).ID("a").Property("Name", "a"),
+		Bean(beanStruct1{}).ID("b").Property("Name", "b"),
+	)
+	ctx, e := NewApplicationContext(beans...)
+	require.Nil(t, e)
+
+	// action
+	bean, e := ctx.GetBean("2")
+
+	// assert
+	require.Nil(t, e)
+	assert.Equal(t, "b", bean.(*beanStruct2).B.Name)
+}
+
+func Test_autowire_definitionReused(t *testing.T) {
+	// arrange
+	type beanStruct1 struct {
+		Name string
+	}
+	type beanStruct2 struct {
+		B *beanStruct1 `gs:"autowire"`
+	}
+	definition := Beans(Bean(beanStruct2{}).ID("2"))[0]
+
+	// action
+	ctx1, e1 := NewApplicationContext(definition, Bean(beanStruct1{}).ID("a").Property("Name", "a").(BeanI))
+	ctx2, e2 := NewApplicationContext(definition, Bean(beanStruct1{}).ID("b").Property("Name", "b").(BeanI))
+
+	// assert
+	require.Nil(t, e1)
+	require.Nil(t, e2)
+	bean1, e := ctx1.GetBean("2")
+	require.Nil(t, e)
+	bean2, e := ctx2.GetBean("2")
+	require.Nil(t, e)
+	assert.Equal(t, "a", bean1.(*beanStruct2).B.Name)
+	assert.Equal(t, "b", bean2.(*beanStruct2).B.Name)
+	assert.Empty(t, definition.GetProperties())
+}
+
+func Test_autowire_innerBean(t *testing.T) {
+	// arrange
+	type beanStruct1 struct{}
+	type beanStruct2 struct {
+		B *beanStruct1 `gs:"autowire"`
+	}
+	type beanStruct3 struct {
+		B *beanStruct2
+	}
+	beans := Beans(
+		Bean(beanStruct3{}).ID("3").Property("B", Bean(beanStruct2{})),
+		Bean(beanStruct1{}).ID("1"),
+	)
+	ctx, e := NewApplicationContext(beans...)
+	require.Nil(t, e)
+
+	// action
+	bean, e := ctx.GetBean("3")
+
+	// assert
+	require.Nil(t, e)
+	assert.NotNil(t, bean.(*beanStruct3).B.B)
+}
+
+func Test_autowire_noCandidate(t *testing.T) {
+	// arrange
+	type beanStruct1 struct{}
+	type beanStruct2 struct {
+		B *beanStruct1 `gs:"autowire"`
+	}
+	beans := Beans(
+		Bean(beanStruct2{}).ID("2"),
+	)
+
+	// action
+	_, e := NewApplicationContext(beans...)
+
+	// assert
+	assert.NotNil(t, e)
+}
+
+func Test_autowire_ambiguous(t *testing.T) {
+	// arrange
+	type beanStruct1 struct{}
+	type beanStruct2 struct {
+		B *beanStruct1 `gs:"autowire"`
+	}
+	beans := Beans(
+		Bean(beanStruct2{}).ID("2"),
+		Bean(beanStruct1{}).ID("a"),
+		Bean(beanStruct1{}).ID("b"),
+	)
+
+	// action
+	_, e := NewApplicationContext(beans...)
+
+	// assert
+	require.NotNil(t, e)
+	assert.Contains(t, e.Error(), "[a b]")
+}
+
+func Test_autowire_unknownID(t *testing.T) {
+	// arrange
+	type beanStruct1 struct{}
+	type beanStruct2 struct {
+		B *beanStruct1 `gs:"autowire,id=c"`
+	}
+	beans := Beans(
+		Bean(beanStruct2{}).ID("2"),
+		Bean(beanStruct1{}).ID("a"),
+	)
+
+	// action
+	_, e := NewApplicationContext(beans...)
+
+	// assert
+	assert.NotNil(t, e)
+}
+
+func Test_autowire_unexportedField(t *testing.T) {
+	// arrange
+	type beanStruct1 struct{}
+	type beanStruct2 struct {
+		b *beanStruct1 `gs:"autowire"`
+	}
+	beans := Beans(
+		Bean(beanStruct2{}).ID("2"),
+		Bean(beanStruct1{}).ID("a"),
+	)
+
+	// action
+	_, e := NewApplicationContext(beans...)
+
+	// assert
+	assert.NotNil(t, e)
+}
+
+func Test_parseAutowireTag(t *testing.T) {
+	// arrange
+	cases := []struct {
+		tag   string
+		wired bool
+		id    string
+		fail  bool
+	}{
+		{"", false, "", false},
+		{"json", false, "", false},
+		{"autowire", true, "", false},
+		{"autowire,id=b_id", true, "b_id", false},
+		{"autowire,id=", false, "", true},
+		{"autowire,aaa", false, "", true},
+	}
+
+	for _, c := range cases {
+		// action
+		wired, id, e := parseAutowireTag(c.tag)
+
+		// assert
+		assert.Equal(t, c.wired, wired, c.tag)
+		assert.Equal(t, c.id, id, c.tag)
+		assert.Equal(t, c.fail, e != nil, c.tag)
+	}
+}
@@ -15,6 +15,7 @@ func Bean(value interface{}) StructBeanI {
 		tvpe:       reflect.TypeOf(value),
 		value:      reflect.ValueOf(value),
 		properties: make(map[string][]BeanI),
+		entries:    make(map[string][]EntryI),
 		init:       nil,
 		finalize:   nil,
 		scope:      Default,
@@ -26,6 +27,33 @@ func Bean(value interface{}) StructBeanI {
 	}
 }
 
+// Value defines a bean whose value is the property with the key in the
+// environment of the context. The value is converted into the type of the
+// field or the argument it's injected into.
+//
+// Bean(...).Property("Port", Value("server.port"))
+func Value(key string) PropertyBeanI {
+	return &propertyBean{
+		key: key,
+	}
+}
+
+// Entry defines a (key,value) pair of a map property.
+//
+// Bean(...).Property(
+//     "Handlers",
+//     Entry("a", Ref("a_handler")),
+//     Entry("b", Bean(...)),
+// )
+//
+// The value can be anything accepted by Beans(...).
+func Entry(key interface{}, value interface{}) EntryI {
+	return &entry{
+		key:   key,
+		value: value,
+	}
+}
+
 func Beans(values ...interface{}) []BeanI {
 
 	beans := make([]BeanI, len(values))
@@ -41,6 +69,9 @@ func Beans(values ...interface{}) []BeanI {
 		case ValueBeanI:
 			beans[i] = value.(BeanI)
 			continue
+		case PropertyBeanI:
+			beans[i] = value.(BeanI)
+			continue
 		default:
 		}
 
@@ -61,9 +92,11 @@ func Chan(value interface{}, buffer int) StructBeanI {
 	return &structBean{
 		tvpe:       dummy.Type(),
 		properties: make(map[string][]BeanI),
+		entries:    make(map[string][]EntryI),
 		init:       nil,
 		finalize:   nil,
 		scope:      Default,
+		buffer:     &buffer,
 		factoryFn: func() interface{} {
 			v := reflect.MakeChan(c, buffer)
 			return v.Interface()
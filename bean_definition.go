@@ -0,0 +1,78 @@
+package gospring
+
+import (
+	"reflect"
+	"sort"
+)
+
+type beanDefinition struct {
+	id           string
+	bean         BeanI
+	dependencies []string
+}
+
+func (definition *beanDefinition) GetDependencies() []string {
+	return append([]string{}, definition.dependencies...)
+}
+
+func (definition *beanDefinition) GetFinalize() *string {
+	return definition.bean.GetFinalize()
+}
+
+func (definition *beanDefinition) GetID() string {
+	return definition.id
+}
+
+func (definition *beanDefinition) GetInit() *string {
+	return definition.bean.GetInit()
+}
+
+func (definition *beanDefinition) GetScope() Scope {
+	return definition.bean.GetScope()
+}
+
+func (definition *beanDefinition) GetType() reflect.Type {
+	return definition.bean.GetType()
+}
+
+// dependenciesOf returns sorted IDs of beans which the bean depends on.
+// Anonymous beans are walked through since they belong to the bean.
+func (g *dependencyGraph) dependenciesOf(bean BeanI) []string {
+
+	var start *dependencyNode
+	for _, n := range g.nodes {
+		if n.bean == bean {
+			start = n
+			break
+		}
+	}
+	if start == nil {
+		return []string{}
+	}
+
+	ids := make(map[string]bool)
+	visited := map[*dependencyNode]bool{start: true}
+	stack := []*dependencyNode{start}
+
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, edge := range n.edges {
+			if !edge.to.anonymous {
+				ids[edge.to.name] = true
+				continue
+			}
+			if !visited[edge.to] {
+				visited[edge.to] = true
+				stack = append(stack, edge.to)
+			}
+		}
+	}
+
+	dependencies := make([]string, 0, len(ids))
+	for id := range ids {
+		dependencies = append(dependencies, id)
+	}
+	sort.Strings(dependencies)
+	return dependencies
+}
@@ -0,0 +1,17 @@
+package gospring
+
+import "reflect"
+
+// BeanDefinitionI is a read-only view of a bean defined in a context.
+type BeanDefinitionI interface {
+
+	// IDs of beans which the bean depends on through fields and factory
+	// arguments, including those of inner beans.
+	GetDependencies() []string
+
+	GetFinalize() *string
+	GetID() string
+	GetInit() *string
+	GetScope() Scope
+	GetType() reflect.Type
+}
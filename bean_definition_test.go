@@ -0,0 +1,215 @@
+package gospring
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Test_introspection_i interface {
+	Name() string
+}
+
+type Test_introspection_struct1 struct {
+	B     *Test_introspection_struct2
+	Inner *Test_introspection_struct2
+}
+
+func (s *Test_introspection_struct1) Start() {}
+
+type Test_introspection_struct2 struct {
+	C *Test_introspection_struct2
+}
+
+func (s *Test_introspection_struct2) Stop() {}
+
+func (s *Test_introspection_struct2) Name() string {
+	return "2"
+}
+
+func Test_GetBeanIDs(t *testing.T) {
+	// arrange
+	ctx, e := NewApplicationContext(Beans(
+		Bean(Test_introspection_struct1{}).
+			ID("a").
+			Prototype().
+			Init("Start").
+			Property("B", Ref("b")).
+			Property("Inner", Bean(Test_introspection_struct2{}).Property("C", Ref("c"))),
+		Bean(Test_introspection_struct2{}).ID("b").Finalize("Stop"),
+		Bean(Test_introspection_struct2{}).ID("c"),
+	)...)
+	require.Nil(t, e)
+
+	// action
+	ids := ctx.GetBeanIDs()
+
+	// assert
+	assert.Equal(t, []string{"a", "b", "c"}, ids)
+}
+
+func Test_GetBeanDefinition(t *testing.T) {
+	// arrange
+	ctx, e := NewApplicationContext(Beans(
+		Bean(Test_introspection_struct1{}).
+			ID("a").
+			Prototype().
+			Init("Start").
+			Property("B", Ref("b")).
+			Property("Inner", Bean(Test_introspection_struct2{}).Property("C", Ref("c"))),
+		Bean(Test_introspection_struct2{}).ID("b").Finalize("Stop"),
+		Bean(Test_introspection_struct2{}).ID("c"),
+	)...)
+	require.Nil(t, e)
+
+	// action
+	a, ea := ctx.GetBeanDefinition("a")
+	b, eb := ctx.GetBeanDefinition("b")
+	_, ec := ctx.GetBeanDefinition("aaa")
+
+	// assert
+	require.Nil(t, ea)
+	assert.Equal(t, "a", a.GetID())
+	assert.Equal(t, reflect.TypeOf(Test_introspection_struct1{}), a.GetType())
+	assert.Equal(t, Prototype, a.GetScope())
+	assert.Equal(t, "Start", *a.GetInit())
+	assert.Nil(t, a.GetFinalize())
+	assert.Equal(t, []string{"b", "c"}, a.GetDependencies())
+	require.Nil(t, eb)
+	assert.Equal(t, "Stop", *b.GetFinalize())
+	assert.Empty(t, b.GetDependencies())
+	assert.True(t, errors.Is(ec, ErrBeanNotFound))
+}
+
+func Test_GetBeanDefinition_parent(t *testing.T) {
+	// arrange
+	parent, e := NewApplicationContext(Beans(
+		Bean(Test_introspection_struct1{}).
+			ID("a").
+			Prototype().
+			Init("Start").
+			Property("B", Ref("b")).
+			Property("Inner", Bean(Test_introspection_struct2{}).Property("C", Ref("c"))),
+		Bean(Test_introspection_struct2{}).ID("b").Finalize("Stop"),
+		Bean(Test_introspection_struct2{}).ID("c"),
+	)...)
+	require.Nil(t, e)
+	ctx, e := Context().Parent(parent).Build()
+	require.Nil(t, e)
+
+	// action
+	definition, e := ctx.GetBeanDefinition("b")
+
+	// assert
+	require.Nil(t, e)
+	assert.Equal(t, "b", definition.GetID())
+}
+
+func Test_IsSingletonInstantiated(t *testing.T) {
+	// arrange
+	ctx, e := NewApplicationContext(Beans(
+		Bean(Test_introspection_struct1{}).
+			ID("a").
+			Prototype().
+			Init("Start").
+			Property("B", Ref("b")).
+			Property("Inner", Bean(Test_introspection_struct2{}).Property("C", Ref("c"))),
+		Bean(Test_introspection_struct2{}).ID("b").Finalize("Stop"),
+		Bean(Test_introspection_struct2{}).ID("c"),
+	)...)
+	require.Nil(t, e)
+	before := ctx.IsSingletonInstantiated("b")
+
+	// action
+	_, e = ctx.GetBean("a")
+
+	// assert
+	require.Nil(t, e)
+	assert.False(t, before)
+	assert.True(t, ctx.IsSingletonInstantiated("b"))
+	assert.False(t, ctx.IsSingletonInstantiated("a"))
+	assert.False(t, ctx.IsSingletonInstantiated("aaa"))
+}
+
+func Test_GetBeanIDsForType(t *testing.T) {
+	// arrange
+	ctx, e := NewApplicationContext(Beans(
+		Bean(Test_introspection_struct1{}).
+			ID("a").
+			Prototype().
+			Init("Start").
+			Property("B", Ref("b")).
+			Property("Inner", Bean(Test_introspection_struct2{}).Property("C", Ref("c"))),
+		Bean(Test_introspection_struct2{}).ID("b").Finalize("Stop"),
+		Bean(Test_introspection_struct2{}).ID("c"),
+	)...)
+	require.Nil(t, e)
+
+	// action
+	byInterface := ctx.GetBeanIDsForType((*Test_introspection_i)(nil))
+	byReflectType := ctx.GetBeanIDsForType(reflect.TypeOf((*Test_introspection_i)(nil)).Elem())
+	byStruct := ctx.GetBeanIDsForType(Test_introspection_struct1{})
+	byPointer := ctx.GetBeanIDsForType(&Test_introspection_struct1{})
+	none := ctx.GetBeanIDsForType(0)
+
+	// assert
+	assert.Equal(t, []string{"b", "c"}, byInterface)
+	assert.Equal(t, []string{"b", "c"}, byReflectType)
+	assert.Equal(t, []string{"a"}, byStruct)
+	assert.Equal(t, []string{"a"}, byPointer)
+	assert.Empty(t, none)
+}
+
+func Test_GetBeansOfType(t *testing.T) {
+	// arrange
+	ctx, e := NewApplicationContext(Beans(
+		Bean(Test_introspection_struct1{}).
+			ID("a").
+			Prototype().
+			Init("Start").
+			Property("B", Ref("b")).
+			Property("Inner", Bean(Test_introspection_struct2{}).Property("C", Ref("c"))),
+		Bean(Test_introspection_struct2{}).ID("b").Finalize("Stop"),
+		Bean(Test_introspection_struct2{}).ID("c"),
+	)...)
+	require.Nil(t, e)
+
+	// action
+	beans, e := ctx.GetBeansOfType((*Test_introspection_i)(nil))
+
+	// assert
+	require.Nil(t, e)
+	require.Len(t, beans, 2)
+	b, _ := ctx.GetBean("b")
+	assert.True(t, beans["b"] == b)
+	assert.Equal(t, "2", beans["c"].(Test_introspection_i).Name())
+}
+
+func Test_GetBeansOfType_skipScopedBeans(t *testing.T) {
+	// arrange
+	scope := newTest_scope()
+	pool := NewPoolScope(0, 1)
+	ctx, e := Context().
+		Scope("custom", scope).
+		Scope("pool", pool).
+		Build(Beans(
+			Bean(Test_introspection_struct2{}).ID("b"),
+			Bean(Test_introspection_struct2{}).ID("request").Scope(Request),
+			Bean(Test_introspection_struct2{}).ID("custom").Scope("custom"),
+			Bean(Test_introspection_struct2{}).ID("pool").Scope("pool"),
+		)...)
+	require.Nil(t, e)
+
+	// action
+	beans, e := ctx.GetBeansOfType((*Test_introspection_i)(nil))
+
+	// assert
+	require.Nil(t, e)
+	require.Len(t, beans, 1)
+	assert.NotNil(t, beans["b"])
+	assert.Empty(t, scope.objects)
+	assert.Empty(t, pool.(*poolScope).borrowed)
+}
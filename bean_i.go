@@ -8,6 +8,13 @@ const (
 	Default   Scope = "Default"
 	Singleton Scope = "Singleton"
 	Prototype Scope = "Prototype"
+
+	// Request is the scope of beans which live for one request. They are
+	// acquired by GetBeanContext(...) or GetBean(...) with a context.Context
+	// returned by WithRequestScope(...), and finalized when the request
+	// scope is closed. They can't be injected into singletons, which are
+	// created out of any request.
+	Request Scope = "Request"
 )
 
 type BeanI interface {
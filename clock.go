@@ -0,0 +1,14 @@
+package gospring
+
+import "time"
+
+type systemClock struct{}
+
+// SystemClock returns a clock which tells time.Now().
+func SystemClock() ClockI {
+	return systemClock{}
+}
+
+func (clock systemClock) Now() time.Time {
+	return time.Now()
+}
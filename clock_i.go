@@ -0,0 +1,9 @@
+package gospring
+
+import "time"
+
+// ClockI tells the current time. It's replaced in tests to drive time-based
+// scopes, e.g. TTLScopeI.Clock(...).
+type ClockI interface {
+	Now() time.Time
+}
@@ -0,0 +1,103 @@
+package gospring
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// condition decides whether a bean should be added into a context. It
+// returns a description of itself which is used in error messages.
+type condition func(ctx *applicationContext) (matched bool, description string)
+
+func onProperty(key string, values ...string) condition {
+	return func(ctx *applicationContext) (bool, string) {
+		description := fmt.Sprintf("condition OnProperty(%v, %v)", key, values)
+
+		value, present := ctx.environment.GetProperty(key)
+		if !present {
+			return false, description
+		}
+		if len(values) == 0 {
+			return true, description
+		}
+		for _, v := range values {
+			if v == value {
+				return true, description
+			}
+		}
+		return false, description
+	}
+}
+
+func onBean(id string, present bool) condition {
+	return func(ctx *applicationContext) (bool, string) {
+		description := fmt.Sprintf("condition OnMissingBean(%v)", id)
+		if present {
+			description = fmt.Sprintf("condition OnBean(%v)", id)
+		}
+
+		_, found := ctx.beanById[id]
+		if !found && ctx.parent != nil {
+			found = ctx.parent.ContainsBean(id)
+		}
+		return found == present, description
+	}
+}
+
+func onBeanType(i interface{}, present bool) condition {
+
+	tvpe := typeOfBean(i)
+
+	return func(ctx *applicationContext) (bool, string) {
+		description := fmt.Sprintf("condition OnMissingBeanType(%v)", tvpe)
+		if present {
+			description = fmt.Sprintf("condition OnBeanType(%v)", tvpe)
+		}
+
+		return ctx.containsBeanOfType(tvpe) == present, description
+	}
+}
+
+// typeOfBean returns the type of i. (*SomeInterface)(nil) is used to
+// specify an interface type, and a reflect.Type is returned as it is.
+func typeOfBean(i interface{}) reflect.Type {
+	if tvpe, ok := i.(reflect.Type); ok {
+		return tvpe
+	}
+	tvpe := reflect.TypeOf(i)
+	if tvpe != nil && tvpe.Kind() == reflect.Ptr && tvpe.Elem().Kind() == reflect.Interface {
+		tvpe = tvpe.Elem()
+	}
+	return tvpe
+}
+
+func when(fn func() bool) condition {
+	return func(ctx *applicationContext) (bool, string) {
+		return fn(), "condition When(...)"
+	}
+}
+
+// matchConditions returns false and the description of the first unmatched
+// condition if any condition of the bean is unmatched.
+func (ctx *applicationContext) matchConditions(bean *structBean) (bool, string) {
+	for _, c := range bean.GetConditions() {
+		if matched, description := c(ctx); !matched {
+			return false, description
+		}
+	}
+	return true, ""
+}
+
+// containsBeanOfType checks whether there is a bean which can be assigned to
+// a field with the type in this context or its ancestors.
+func (ctx *applicationContext) containsBeanOfType(tvpe reflect.Type) bool {
+	for _, bean := range ctx.beanById {
+		if isAutowireCandidate(tvpe, bean.GetType()) {
+			return true
+		}
+	}
+	if p, ok := ctx.parent.(*applicationContext); ok {
+		return p.containsBeanOfType(tvpe)
+	}
+	return false
+}
@@ -0,0 +1,180 @@
+package gospring
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Test_condition_interface interface {
+	Name() string
+}
+
+type Test_condition_struct struct {
+	N string
+}
+
+func (s *Test_condition_struct) Name() string {
+	return s.N
+}
+
+func Test_OnMissingBean_overriddenByUser(t *testing.T) {
+	// arrange
+	beans := Beans(
+		Bean(Test_condition_struct{}).ID("1").OnMissingBean("2").Property("N", "default"),
+		Bean(Test_condition_struct{}).ID("2").Property("N", "user"),
+	)
+
+	// action
+	ctx, e := NewApplicationContext(beans...)
+
+	// assert
+	require.Nil(t, e)
+	assert.False(t, ctx.ContainsBean("1"))
+	assert.True(t, ctx.ContainsBean("2"))
+}
+
+func Test_OnMissingBean_defaultIsUsed(t *testing.T) {
+	// arrange
+	beans := Beans(
+		Bean(Test_condition_struct{}).ID("1").OnMissingBean("2").Property("N", "default"),
+	)
+
+	// action
+	ctx, e := NewApplicationContext(beans...)
+
+	// assert
+	require.Nil(t, e)
+	assert.True(t, ctx.ContainsBean("1"))
+}
+
+func Test_OnMissingBeanType_overriddenByUser(t *testing.T) {
+	// arrange
+	type beanStruct struct {
+		I Test_condition_interface `gs:"autowire"`
+	}
+	beans := Beans(
+		Bean(Test_condition_struct{}).
+			ID("default").
+			OnMissingBeanType((*Test_condition_interface)(nil)).
+			Property("N", "default"),
+		Bean(beanStruct{}).ID("1"),
+		Bean(Test_condition_struct{}).ID("user").Property("N", "user"),
+	)
+	ctx, e := NewApplicationContext(beans...)
+	require.Nil(t, e)
+
+	// action
+	bean, e := ctx.GetBean("1")
+
+	// assert
+	require.Nil(t, e)
+	assert.Equal(t, "user", bean.(*beanStruct).I.Name())
+}
+
+func Test_OnBean(t *testing.T) {
+	// arrange
+	beans := Beans(
+		Bean(Test_condition_struct{}).ID("1").OnBean("2"),
+		Bean(Test_condition_struct{}).ID("2").OnBean("3"),
+	)
+
+	// action
+	ctx, e := NewApplicationContext(beans...)
+
+	// assert
+	require.Nil(t, e)
+	assert.False(t, ctx.ContainsBean("1"))
+	assert.False(t, ctx.ContainsBean("2"))
+}
+
+func Test_OnBeanType(t *testing.T) {
+	// arrange
+	type beanStruct struct{}
+	beans := Beans(
+		Bean(beanStruct{}).ID("1").OnBeanType(Test_condition_struct{}),
+		Bean(Test_condition_struct{}).ID("2"),
+	)
+
+	// action
+	ctx, e := NewApplicationContext(beans...)
+
+	// assert
+	require.Nil(t, e)
+	assert.True(t, ctx.ContainsBean("1"))
+}
+
+func Test_OnProperty(t *testing.T) {
+	// arrange
+	type beanStruct struct{}
+	beans := Beans(
+		Bean(beanStruct{}).ID("set").OnProperty("a"),
+		Bean(beanStruct{}).ID("unset").OnProperty("b"),
+		Bean(beanStruct{}).ID("equal").OnProperty("a", "x", "1"),
+		Bean(beanStruct{}).ID("notEqual").OnProperty("a", "x"),
+	)
+
+	// action
+	ctx, e := Context().Properties(map[string]string{"a": "1"}).Build(beans...)
+
+	// assert
+	require.Nil(t, e)
+	assert.True(t, ctx.ContainsBean("set"))
+	assert.False(t, ctx.ContainsBean("unset"))
+	assert.True(t, ctx.ContainsBean("equal"))
+	assert.False(t, ctx.ContainsBean("notEqual"))
+}
+
+func Test_When(t *testing.T) {
+	// arrange
+	type beanStruct struct{}
+	beans := Beans(
+		Bean(beanStruct{}).ID("1").When(func() bool { return true }),
+		Bean(beanStruct{}).ID("2").When(func() bool { return false }),
+	)
+
+	// action
+	ctx, e := NewApplicationContext(beans...)
+
+	// assert
+	require.Nil(t, e)
+	assert.True(t, ctx.ContainsBean("1"))
+	assert.False(t, ctx.ContainsBean("2"))
+}
+
+func Test_condition_refToSkippedBean(t *testing.T) {
+	// arrange
+	type beanStruct struct {
+		B *beanStruct
+	}
+	beans := Beans(
+		Bean(beanStruct{}).ID("1").Property("B", Ref("2")),
+		Bean(beanStruct{}).ID("2").OnProperty("a"),
+	)
+
+	// action
+	_, e := NewApplicationContext(beans...)
+
+	// assert
+	require.NotNil(t, e)
+	assert.Contains(t, e.Error(), "OnProperty")
+}
+
+func Test_condition_innerBean(t *testing.T) {
+	// arrange
+	type beanStruct struct {
+		B *beanStruct
+	}
+	beans := Beans(
+		Bean(beanStruct{}).ID("1").Property("B",
+			Bean(beanStruct{}).When(func() bool { return true }),
+		),
+	)
+
+	// action
+	_, e := NewApplicationContext(beans...)
+
+	// assert
+	assert.NotNil(t, e)
+}
@@ -0,0 +1,111 @@
+package gospring
+
+type contextBuilder struct {
+	eager         bool
+	allowCircular bool
+	parent        ApplicationContextI
+	profiles      []string
+	scopes        map[Scope]ScopeI
+	sources       []PropertySourceI
+
+	// skipValidation is only set by tests to reach the checks done while
+	// creating beans.
+	skipValidation bool
+}
+
+// Context creates a builder of ApplicationContextI with options.
+//
+// ctx, e := Context().
+//     Eager().
+//     Build(
+//         Bean(...),
+//         Bean(...),
+//     )
+func Context() ContextBuilderI {
+	return &contextBuilder{
+		eager:    false,
+		parent:   nil,
+		profiles: []string{},
+		scopes:   make(map[Scope]ScopeI),
+		sources:  []PropertySourceI{},
+	}
+}
+
+func (builder *contextBuilder) Build(beans ...BeanI) (ApplicationContextI, error) {
+	ctx, e := newApplicationContext(builder, beans)
+	if e != nil {
+		return nil, e
+	}
+	return ctx, nil
+}
+
+// Validate checks the beans like Build(...) without creating any of them,
+// e.g. fields and methods which don't exist, or values which can't be
+// injected. All problems found are returned in a *ValidationError.
+func (builder *contextBuilder) Validate(beans ...BeanI) error {
+	_, e := prepareApplicationContext(builder, beans)
+	return e
+}
+
+// Eager makes all singletons, except the ones marked as Lazy(), be created
+// while building the context.
+func (builder *contextBuilder) Eager() ContextBuilderI {
+	builder.eager = true
+	return builder
+}
+
+// AllowCircularReferences makes singletons be able to depend on each other
+// through properties, e.g. A.B refers to B and B.A refers to A. Instances
+// are created first and injected afterwards, and initializers of beans in
+// a circle are called after the whole circle is wired.
+//
+// Circles through factory arguments or prototypes are still rejected.
+// Singletons are created by one goroutine at a time, whose initializers and
+// factories can still get other beans.
+func (builder *contextBuilder) AllowCircularReferences() ContextBuilderI {
+	builder.allowCircular = true
+	return builder
+}
+
+// Lazy makes singletons be created at the first time they are acquired.
+// It's the default behavior.
+func (builder *contextBuilder) Lazy() ContextBuilderI {
+	builder.eager = false
+	return builder
+}
+
+// Parent sets the parent context. Beans which can't be found in the
+// context, either by GetBean(...) or Ref(...), are acquired from the
+// parent.
+func (builder *contextBuilder) Parent(parent ApplicationContextI) ContextBuilderI {
+	builder.parent = parent
+	return builder
+}
+
+// Profiles sets names of active profiles. Beans with Profile(...) which
+// don't match them are ignored.
+func (builder *contextBuilder) Profiles(names ...string) ContextBuilderI {
+	builder.profiles = append(builder.profiles, names...)
+	return builder
+}
+
+// Scope registers the scope with the name, so beans can be put into it by
+// StructBeanI.Scope(name). Names of built-in scopes can't be registered.
+func (builder *contextBuilder) Scope(name Scope, scope ScopeI) ContextBuilderI {
+	builder.scopes[name] = scope
+	return builder
+}
+
+// Properties adds a property source from the map. It's the same as
+// PropertySources(MapSource("properties", properties)).
+func (builder *contextBuilder) Properties(properties map[string]string) ContextBuilderI {
+	return builder.PropertySources(MapSource("properties", properties))
+}
+
+// PropertySources adds sources into the environment of the context. Sources
+// added first have higher precedence. Sources of the parent context have the
+// lowest precedence.
+func (builder *contextBuilder) PropertySources(sources ...PropertySourceI) ContextBuilderI {
+	builder.sources = append(builder.sources, sources...)
+	return builder
+}
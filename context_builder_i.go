@@ -0,0 +1,14 @@
+package gospring
+
+type ContextBuilderI interface {
+	AllowCircularReferences() ContextBuilderI
+	Build(beans ...BeanI) (ApplicationContextI, error)
+	Eager() ContextBuilderI
+	Lazy() ContextBuilderI
+	Parent(parent ApplicationContextI) ContextBuilderI
+	Profiles(names ...string) ContextBuilderI
+	Properties(properties map[string]string) ContextBuilderI
+	PropertySources(sources ...PropertySourceI) ContextBuilderI
+	Scope(name Scope, scope ScopeI) ContextBuilderI
+	Validate(beans ...BeanI) error
+}
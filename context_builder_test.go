@@ -0,0 +1,276 @@
+package gospring
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Test_Build_eager_struct1 struct {
+	B       *Test_Build_eager_struct2
+	created *[]string
+}
+
+func (s *Test_Build_eager_struct1) Init() {
+	*s.B.created = append(*s.B.created, "1")
+}
+
+type Test_Build_eager_struct2 struct {
+	created *[]string
+}
+
+func (s *Test_Build_eager_struct2) Init() {
+	*s.created = append(*s.created, "2")
+}
+
+func (s *Test_Build_eager_struct2) Finalize() {
+	*s.created = append(*s.created, "finalize 2")
+}
+
+type Test_Build_eager_fail_struct struct{}
+
+func (s *Test_Build_eager_fail_struct) Init() error {
+	return fmt.Errorf("")
+}
+
+func Test_Build_eager(t *testing.T) {
+	// arrange
+	created := []string{}
+	beans := Beans(
+		Bean(Test_Build_eager_struct1{}).ID("1").Property("B", Ref("2")),
+		Bean(Test_Build_eager_struct2{created: &created}).ID("2"),
+	)
+
+	// action
+	ctx, e := Context().Eager().Build(beans...)
+
+	// assert
+	require.Nil(t, e)
+	require.NotNil(t, ctx)
+	assert.Equal(t, []string{"2", "1"}, created)
+}
+
+func Test_Build_lazy(t *testing.T) {
+	// arrange
+	created := []string{}
+	beans := Beans(
+		Bean(Test_Build_eager_struct2{created: &created}).ID("2"),
+	)
+
+	// action
+	_, e := Context().Build(beans...)
+
+	// assert
+	require.Nil(t, e)
+	assert.Empty(t, created)
+}
+
+func Test_Build_eagerBean(t *testing.T) {
+	// arrange
+	created := []string{}
+	beans := Beans(
+		Bean(Test_Build_eager_struct2{created: &created}).ID("2").Eager(),
+	)
+
+	// action
+	_, e := NewApplicationContext(beans...)
+
+	// assert
+	require.Nil(t, e)
+	assert.Equal(t, []string{"2"}, created)
+}
+
+func Test_Build_lazyBeanInEagerContext(t *testing.T) {
+	// arrange
+	created := []string{}
+	beans := Beans(
+		Bean(Test_Build_eager_struct2{created: &created}).ID("2").Lazy(),
+	)
+
+	// action
+	_, e := Context().Eager().Build(beans...)
+
+	// assert
+	require.Nil(t, e)
+	assert.Empty(t, created)
+}
+
+func Test_Build_eagerSkipPrototype(t *testing.T) {
+	// arrange
+	created := []string{}
+	beans := Beans(
+		Bean(Test_Build_eager_struct2{created: &created}).ID("2").Prototype(),
+	)
+
+	// action
+	_, e := Context().Eager().Build(beans...)
+
+	// assert
+	require.Nil(t, e)
+	assert.Empty(t, created)
+}
+
+func Test_Build_eagerFailed(t *testing.T) {
+	// arrange
+	created := []string{}
+	beans := Beans(
+		Bean(Test_Build_eager_struct2{created: &created}).ID("2"),
+		Bean(Test_Build_eager_fail_struct{}).ID("3"),
+	)
+
+	// action
+	ctx, e := Context().Eager().Build(beans...)
+
+	// assert
+	assert.Nil(t, ctx)
+	assert.NotNil(t, e)
+	assert.Equal(t, []string{"2", "finalize 2"}, created)
+}
+
+type Test_Build_parent_struct struct {
+	finalized bool
+}
+
+func (s *Test_Build_parent_struct) Finalize() {
+	s.finalized = true
+}
+
+func Test_Build_parent(t *testing.T) {
+	// arrange
+	type childStruct struct {
+		P *Test_Build_parent_struct
+	}
+	parent, e := NewApplicationContext(Beans(
+		Bean(Test_Build_parent_struct{}).ID("p"),
+	)...)
+	require.Nil(t, e)
+	child, e := Context().Parent(parent).Build(Beans(
+		Bean(childStruct{}).ID("c").Property("P", Ref("p")),
+	)...)
+	require.Nil(t, e)
+
+	// action
+	c, e := child.GetBean("c")
+	require.Nil(t, e)
+	p1, e := child.GetBean("p")
+	require.Nil(t, e)
+	p2, e := parent.GetBean("p")
+	require.Nil(t, e)
+
+	// assert
+	assert.True(t, p1 == p2)
+	assert.True(t, c.(*childStruct).P == p2)
+	assert.True(t, child.ContainsBean("p"))
+	assert.False(t, parent.ContainsBean("c"))
+}
+
+func Test_Build_parentNotFinalizedByChild(t *testing.T) {
+	// arrange
+	type childStruct struct {
+		P *Test_Build_parent_struct
+	}
+	parent, e := NewApplicationContext(Beans(
+		Bean(Test_Build_parent_struct{}).ID("p"),
+	)...)
+	require.Nil(t, e)
+	child, e := Context().Parent(parent).Build(Beans(
+		Bean(childStruct{}).ID("c").Property("P", Ref("p")),
+	)...)
+	require.Nil(t, e)
+	c, e := child.GetBean("c")
+	require.Nil(t, e)
+
+	// action
+	e = child.Finalize()
+
+	// assert
+	require.Nil(t, e)
+	assert.False(t, c.(*childStruct).P.finalized)
+}
+
+func Test_Build_parentIsShadowed(t *testing.T) {
+	// arrange
+	type beanStruct struct {
+		Name string
+	}
+	parent, e := NewApplicationContext(Beans(
+		Bean(beanStruct{}).ID("1").Property("Name", "parent"),
+	)...)
+	require.Nil(t, e)
+	child, e := Context().Parent(parent).Build(Beans(
+		Bean(beanStruct{}).ID("1").Property("Name", "child"),
+	)...)
+	require.Nil(t, e)
+
+	// action
+	bean, e := child.GetBean("1")
+
+	// assert
+	require.Nil(t, e)
+	assert.Equal(t, "child", bean.(*beanStruct).Name)
+}
+
+func Test_Build_parentNotFound(t *testing.T) {
+	// arrange
+	type childStruct struct {
+		P *Test_Build_parent_struct
+	}
+	parent, e := NewApplicationContext()
+	require.Nil(t, e)
+
+	// action
+	_, e = Context().Parent(parent).Build(Beans(
+		Bean(childStruct{}).ID("c").Property("P", Ref("p")),
+	)...)
+
+	// assert
+	assert.NotNil(t, e)
+}
+
+func Test_Build_parentAutowire(t *testing.T) {
+	// arrange
+	type childStruct struct {
+		P *Test_Build_parent_struct `gs:"autowire"`
+	}
+	parent, e := NewApplicationContext(Beans(
+		Bean(Test_Build_parent_struct{}).ID("p"),
+	)...)
+	require.Nil(t, e)
+	child, e := Context().Parent(parent).Build(Beans(
+		Bean(childStruct{}).ID("c"),
+	)...)
+	require.Nil(t, e)
+
+	// action
+	c, e := child.GetBean("c")
+	require.Nil(t, e)
+	p, e := parent.GetBean("p")
+	require.Nil(t, e)
+
+	// assert
+	assert.True(t, c.(*childStruct).P == p)
+}
+
+func Test_Build_parentSingletonToElem(t *testing.T) {
+	// arrange
+	type childStruct struct {
+		P Test_Build_parent_struct
+	}
+	parent, e := NewApplicationContext(Beans(
+		Bean(Test_Build_parent_struct{}).ID("p").Singleton(),
+	)...)
+	require.Nil(t, e)
+	child, e := (&contextBuilder{parent: parent, skipValidation: true}).Build(Beans(
+		Bean(childStruct{}).ID("c").Property("P", Ref("p")),
+	)...)
+	require.Nil(t, e)
+
+	// action
+	c, e := child.GetBean("c")
+
+	// assert
+	assert.Nil(t, c)
+	assert.NotNil(t, e)
+}
@@ -0,0 +1,152 @@
+package gospring
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// convertString converts a string into a value with the type. Supported
+// types are strings, booleans, numbers, time.Duration and pointers to them.
+func convertString(s string, tvpe reflect.Type) (reflect.Value, error) {
+
+	if tvpe.Kind() == reflect.Ptr {
+		elem, e := convertString(s, tvpe.Elem())
+		if e != nil {
+			return reflect.Value{}, e
+		}
+		ptr := reflect.New(tvpe.Elem())
+		ptr.Elem().Set(elem)
+		return ptr, nil
+	}
+
+	value := reflect.New(tvpe).Elem()
+
+	switch tvpe.Kind() {
+	case reflect.String:
+		value.SetString(s)
+	case reflect.Interface:
+		if !reflect.TypeOf(s).AssignableTo(tvpe) {
+			return reflect.Value{}, fmt.Errorf("Can't convert [%v] to [%v]", s, tvpe)
+		}
+		value.Set(reflect.ValueOf(s))
+	case reflect.Bool:
+		b, e := strconv.ParseBool(s)
+		if e != nil {
+			return reflect.Value{}, fmt.Errorf("Can't convert [%v] to [%v]. Caused by: %w", s, tvpe, e)
+		}
+		value.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if tvpe == durationType {
+			d, e := time.ParseDuration(s)
+			if e != nil {
+				return reflect.Value{}, fmt.Errorf("Can't convert [%v] to [%v]. Caused by: %w", s, tvpe, e)
+			}
+			value.SetInt(int64(d))
+			break
+		}
+		i, e := strconv.ParseInt(s, 0, tvpe.Bits())
+		if e != nil {
+			return reflect.Value{}, fmt.Errorf("Can't convert [%v] to [%v]. Caused by: %w", s, tvpe, e)
+		}
+		value.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u, e := strconv.ParseUint(s, 0, tvpe.Bits())
+		if e != nil {
+			return reflect.Value{}, fmt.Errorf("Can't convert [%v] to [%v]. Caused by: %w", s, tvpe, e)
+		}
+		value.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, e := strconv.ParseFloat(s, tvpe.Bits())
+		if e != nil {
+			return reflect.Value{}, fmt.Errorf("Can't convert [%v] to [%v]. Caused by: %w", s, tvpe, e)
+		}
+		value.SetFloat(f)
+	default:
+		return reflect.Value{}, fmt.Errorf("Can't convert [%v] to [%v]", s, tvpe)
+	}
+
+	return value, nil
+}
+
+// isConvertibleType checks whether values of a type may be converted into
+// another type by convertValue(...). Numbers aren't converted into strings,
+// which reflect does as runes, e.g. 65 into "A".
+func isConvertibleType(from, to reflect.Type) bool {
+	switch {
+	case from.AssignableTo(to):
+		return true
+	case isNumberKind(from.Kind()) && isNumberKind(to.Kind()):
+		return true
+	default:
+		return from.Kind() == to.Kind() && from.ConvertibleTo(to)
+	}
+}
+
+// convertValue converts the value into the type. Besides assignable values
+// and values of named types, e.g. a string into a type based on string, only
+// numbers which keep their values are converted, e.g. 1 into 1.0 but not
+// 80.9 into 80 or -1 into a uint.
+func convertValue(value reflect.Value, to reflect.Type) (reflect.Value, bool) {
+
+	from := value.Type()
+	if !isConvertibleType(from, to) {
+		return reflect.Value{}, false
+	}
+	if from.AssignableTo(to) || !isNumberKind(from.Kind()) {
+		return value.Convert(to), true
+	}
+
+	// a round trip keeps the value but not the sign, e.g. -1 into a uint
+	negative := isIntKind(from.Kind()) && value.Int() < 0 ||
+		isFloatKind(from.Kind()) && value.Float() < 0
+	if negative && isUintKind(to.Kind()) {
+		return reflect.Value{}, false
+	}
+
+	converted := value.Convert(to)
+	if isUintKind(from.Kind()) && isIntKind(to.Kind()) && converted.Int() < 0 {
+		return reflect.Value{}, false
+	}
+	if converted.Convert(from).Interface() != value.Interface() {
+		return reflect.Value{}, false
+	}
+	return converted, true
+}
+
+// convertElem converts the value which the pointer points to into the type.
+func convertElem(ptr reflect.Value, to reflect.Type) (reflect.Value, bool) {
+	if ptr.Kind() != reflect.Ptr || ptr.IsNil() {
+		return reflect.Value{}, false
+	}
+	return convertValue(ptr.Elem(), to)
+}
+
+func isNumberKind(kind reflect.Kind) bool {
+	return isIntKind(kind) || isUintKind(kind) || isFloatKind(kind)
+}
+
+func isIntKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	default:
+		return false
+	}
+}
+
+func isUintKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	default:
+		return false
+	}
+}
+
+func isFloatKind(kind reflect.Kind) bool {
+	return kind == reflect.Float32 || kind == reflect.Float64
+}
@@ -0,0 +1,125 @@
+package gospring
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_convertString(t *testing.T) {
+	// arrange
+	type myString string
+	cases := []struct {
+		s        string
+		expected interface{}
+	}{
+		{"abc", "abc"},
+		{"abc", myString("abc")},
+		{"true", true},
+		{"-12", int(-12)},
+		{"0x10", int64(16)},
+		{"255", uint8(255)},
+		{"1.5", float64(1.5)},
+		{"1m30s", 90 * time.Second},
+	}
+
+	for _, c := range cases {
+		// action
+		value, e := convertString(c.s, reflect.TypeOf(c.expected))
+
+		// assert
+		if assert.Nil(t, e, c.s) {
+			assert.Equal(t, c.expected, value.Interface(), c.s)
+		}
+	}
+}
+
+func Test_convertString_pointer(t *testing.T) {
+	// arrange
+
+	// action
+	value, e := convertString("1", reflect.TypeOf((*int)(nil)))
+
+	// assert
+	assert.Nil(t, e)
+	assert.Equal(t, 1, *value.Interface().(*int))
+}
+
+func Test_convertString_failed(t *testing.T) {
+	// arrange
+	cases := []struct {
+		s    string
+		tvpe reflect.Type
+	}{
+		{"aaa", reflect.TypeOf(true)},
+		{"aaa", reflect.TypeOf(0)},
+		{"256", reflect.TypeOf(uint8(0))},
+		{"aaa", reflect.TypeOf(0.0)},
+		{"aaa", reflect.TypeOf(time.Duration(0))},
+		{"aaa", reflect.TypeOf(struct{}{})},
+	}
+
+	for _, c := range cases {
+		// action
+		_, e := convertString(c.s, c.tvpe)
+
+		// assert
+		assert.NotNil(t, e, "%v %v", c.s, c.tvpe)
+	}
+}
+
+func Test_convertValue(t *testing.T) {
+	// arrange
+	type myString string
+	cases := []struct {
+		from, to interface{}
+		expected interface{}
+	}{
+		{"a", "", "a"},
+		{"a", myString(""), myString("a")},
+		{1, int64(0), int64(1)},
+		{1, 0.0, 1.0},
+		{80.0, 0, 80},
+		{int64(127), int8(0), int8(127)},
+		{uint8(255), 0, 255},
+	}
+
+	for _, c := range cases {
+		// action
+		value, ok := convertValue(reflect.ValueOf(c.from), reflect.TypeOf(c.to))
+
+		// assert
+		require.True(t, ok, "%v to %T", c.from, c.to)
+		assert.Equal(t, c.expected, value.Interface(), "%v to %T", c.from, c.to)
+	}
+}
+
+func Test_convertValue_fail(t *testing.T) {
+	// arrange
+	type myString string
+	cases := []struct {
+		from, to interface{}
+	}{
+		{65, ""},
+		{uint8(65), myString("")},
+		{"a", 0},
+		{80.9, 0},
+		{128, int8(0)},
+		{-1, uint(0)},
+		{-1.0, uint(0)},
+		{uint64(1 << 63), int64(0)},
+		{[]byte("a"), ""},
+		{1, struct{}{}},
+	}
+
+	for _, c := range cases {
+		// action
+		_, ok := convertValue(reflect.ValueOf(c.from), reflect.TypeOf(c.to))
+
+		// assert
+		assert.False(t, ok, "%v to %T", c.from, c.to)
+	}
+}
@@ -0,0 +1,201 @@
+package gospring
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"runtime"
+	"strconv"
+	"sync"
+)
+
+// creation is the state of a call chain which creates beans. context is
+// given by GetBeanContext(...), and the others are used to create
+// singletons in a context allowing circular references, which are owned by
+// the goroutine holding ctx.creationLock.
+type creation struct {
+	// context may hold a request scope. It's nil while creating singletons.
+	context context.Context
+
+	// stack is singletons whose properties are being injected
+	stack []*creationFrame
+
+	// early are instances exposed before they are initialized, so beans in
+	// a circle can be injected with each other
+	early map[BeanI]*creationFrame
+
+	// deferred are singletons which are wired, but wait for the root of
+	// their circle to be wired before being initialized
+	deferred []*creationFrame
+
+	// outer is the chain of the same goroutine which calls an initializer
+	// or a factory getting beans by this chain
+	outer *creation
+}
+
+type creationFrame struct {
+	bean  BeanI
+	value *reflect.Value
+	path  resolutionPath
+
+	// index is the position in the stack, and low is the lowest position
+	// reached by the bean or beans it depends on. The bean is the root of
+	// a circle if they are the same.
+	index int
+	low   int
+
+	// deferred is the number of deferred singletons when the bean starts
+	// being wired
+	deferred int
+}
+
+// creationLock is held by the goroutine which creates singletons in a
+// context allowing circular references. It's reentrant, so initializers and
+// factories can get other beans, which are created by inner chains.
+type creationLock struct {
+	lock  sync.Mutex
+	cond  *sync.Cond
+	owner uint64
+
+	// chains are chains of the owner, the innermost last
+	chains []*creation
+}
+
+// acquire waits until no other goroutine holds the lock, and starts a chain
+// inside the chains of the current goroutine.
+func (l *creationLock) acquire() *creation {
+	id := goroutineID()
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if l.cond == nil {
+		l.cond = sync.NewCond(&l.lock)
+	}
+	for len(l.chains) > 0 && l.owner != id {
+		l.cond.Wait()
+	}
+	l.owner = id
+
+	c := &creation{
+		early: make(map[BeanI]*creationFrame),
+	}
+	if len(l.chains) > 0 {
+		c.outer = l.chains[len(l.chains)-1]
+	}
+	l.chains = append(l.chains, c)
+	return c
+}
+
+// release ends the innermost chain, and lets other goroutines hold the lock
+// after the outermost one.
+func (l *creationLock) release() {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.chains = l.chains[:len(l.chains)-1]
+	if len(l.chains) == 0 {
+		l.owner = 0
+		l.cond.Broadcast()
+	}
+}
+
+// goroutineID returns the ID of the current goroutine, which is parsed from
+// the header of its stack, e.g. "goroutine 18 [running]:".
+func goroutineID() uint64 {
+	var buf [64]byte
+	fields := bytes.Fields(buf[:runtime.Stack(buf[:], false)])
+	if len(fields) < 2 {
+		return 0
+	}
+	id, _ := strconv.ParseUint(string(fields[1]), 10, 64)
+	return id
+}
+
+// getCircularSingletonBean creates the singleton in the way which allows
+// circular references between properties of singletons. An instance is
+// exposed to beans depending on it once it's created by its factory, and
+// beans in a circle are initialized only after the whole circle is wired.
+// Instances are also exposed to inner chains, e.g. when an initializer gets
+// a bean depending on the bean being initialized.
+func (ctx *applicationContext) getCircularSingletonBean(bean BeanI, path resolutionPath, c *creation) (*reflect.Value, error) {
+
+	if c == nil || c.early == nil {
+		c = ctx.creationLock.acquire()
+		defer ctx.creationLock.release()
+
+		ctx.lock.RLock()
+		value, present := ctx.singletons[bean]
+		ctx.lock.RUnlock()
+
+		if present {
+			return value, nil
+		}
+	}
+
+	if frame, present := c.early[bean]; present {
+		// beans above it in the stack are wired in a circle with it
+		if top := c.stack[len(c.stack)-1]; frame.low < top.low {
+			top.low = frame.low
+		}
+		return frame.value, nil
+	}
+
+	for outer := c.outer; outer != nil; outer = outer.outer {
+		if frame, present := outer.early[bean]; present {
+			return frame.value, nil
+		}
+	}
+
+	value, e := ctx.instantiateBean(bean, path, c)
+	if e != nil {
+		return nil, e
+	}
+
+	frame := &creationFrame{
+		bean:     bean,
+		value:    value,
+		path:     path,
+		index:    len(c.stack),
+		low:      len(c.stack),
+		deferred: len(c.deferred),
+	}
+	c.stack = append(c.stack, frame)
+	c.early[bean] = frame
+
+	e = ctx.populateBean(bean, value, path, c)
+	c.stack = c.stack[:len(c.stack)-1]
+	if e != nil {
+		return nil, e
+	}
+
+	if frame.low < frame.index {
+		// the root of the circle is still being wired
+		if parent := c.stack[len(c.stack)-1]; frame.low < parent.low {
+			parent.low = frame.low
+		}
+		c.deferred = append(c.deferred, frame)
+		return value, nil
+	}
+
+	// beans wired after the root are initialized in the order they are
+	// wired, and then the root
+	frames := append([]*creationFrame{}, c.deferred[frame.deferred:]...)
+	frames = append(frames, frame)
+	c.deferred = c.deferred[:frame.deferred]
+
+	// a bean is exposed until it's published, since its initializer may get
+	// beans depending on it
+	for _, f := range frames {
+		if e := ctx.initializeBean(f.bean, f.value, f.path); e != nil {
+			return nil, e
+		}
+		ctx.lock.Lock()
+		ctx.singletons[f.bean] = f.value
+		ctx.singletonList.PushBack(f.bean)
+		ctx.lock.Unlock()
+		delete(c.early, f.bean)
+	}
+
+	return value, nil
+}
@@ -0,0 +1,227 @@
+package gospring
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Test_circular_struct struct {
+	Name  string
+	Next  *Test_circular_struct
+	Other *Test_circular_struct
+	wired bool
+}
+
+var Test_circular_inits []string
+
+func (s *Test_circular_struct) Init() {
+	// the whole circle is wired when any of them is initialized
+	s.wired = s.Next != nil && s.Next.Next != nil
+	if s.Name != "" {
+		Test_circular_inits = append(Test_circular_inits, s.Name)
+	}
+}
+
+func newTest_circular_struct(next *Test_circular_struct) *Test_circular_struct {
+	return &Test_circular_struct{Next: next}
+}
+
+func Test_AllowCircularReferences_rejectedByDefault(t *testing.T) {
+	// arrange
+	beans := Beans(
+		Bean(Test_circular_struct{}).ID("a").Property("Next", Ref("b")),
+		Bean(Test_circular_struct{}).ID("b").Property("Next", Ref("a")),
+	)
+
+	// action
+	ctx, e := NewApplicationContext(beans...)
+
+	// assert
+	assert.Nil(t, ctx)
+	var ce *CircularDependencyError
+	assert.True(t, errors.As(e, &ce))
+}
+
+func Test_AllowCircularReferences(t *testing.T) {
+	// arrange
+	ctx, e := Context().AllowCircularReferences().Build(Beans(
+		Bean(Test_circular_struct{}).ID("a").Property("Next", Ref("b")),
+		Bean(Test_circular_struct{}).ID("b").Singleton().Property("Next", Ref("a")),
+	)...)
+	require.Nil(t, e)
+
+	// action
+	a, e1 := ctx.GetBean("a")
+	b, e2 := ctx.GetBean("b")
+
+	// assert
+	require.Nil(t, e1)
+	require.Nil(t, e2)
+	assert.True(t, a.(*Test_circular_struct).Next == b)
+	assert.True(t, b.(*Test_circular_struct).Next == a)
+	assert.True(t, a.(*Test_circular_struct).wired)
+	assert.True(t, b.(*Test_circular_struct).wired)
+}
+
+func Test_AllowCircularReferences_initOrder(t *testing.T) {
+	// arrange
+	Test_circular_inits = []string{}
+	ctx, e := Context().AllowCircularReferences().Eager().Build(Beans(
+		Bean(Test_circular_struct{}).ID("a").
+			Property("Name", "a").Property("Next", Ref("b")),
+		Bean(Test_circular_struct{}).ID("b").
+			Property("Name", "b").Property("Next", Ref("c")),
+		Bean(Test_circular_struct{}).ID("c").
+			Property("Name", "c").Property("Next", Ref("a")).Property("Other", Ref("d")),
+		Bean(Test_circular_struct{}).ID("d").
+			Property("Name", "d"),
+	)...)
+	require.Nil(t, e)
+
+	// action
+	e = ctx.Finalize()
+
+	// assert
+	require.Nil(t, e)
+	assert.Equal(t, []string{"d", "c", "b", "a"}, Test_circular_inits)
+	for _, id := range []string{"a", "b", "c", "d"} {
+		assert.True(t, ctx.IsSingletonInstantiated(id))
+	}
+}
+
+func Test_AllowCircularReferences_self(t *testing.T) {
+	// arrange
+	ctx, e := Context().AllowCircularReferences().Build(Beans(
+		Bean(Test_circular_struct{}).ID("a").Property("Next", Ref("a")),
+	)...)
+	require.Nil(t, e)
+
+	// action
+	a, e := ctx.GetBean("a")
+
+	// assert
+	require.Nil(t, e)
+	assert.True(t, a.(*Test_circular_struct).Next == a)
+}
+
+func Test_AllowCircularReferences_factoryArgument(t *testing.T) {
+	// arrange
+	beans := Beans(
+		Bean(Test_circular_struct{}).ID("a").Property("Next", Ref("b")),
+		Bean(Test_circular_struct{}).ID("b").Factory(newTest_circular_struct, Ref("a")),
+	)
+
+	// action
+	ctx, e := Context().AllowCircularReferences().Build(beans...)
+
+	// assert
+	assert.Nil(t, ctx)
+	var ce *CircularDependencyError
+	require.True(t, errors.As(e, &ce))
+	assert.Equal(t, []string{"b", "factory arg #0", "a", "Next", "b"}, ce.Path)
+}
+
+func Test_AllowCircularReferences_prototype(t *testing.T) {
+	// arrange
+	beans := Beans(
+		Bean(Test_circular_struct{}).ID("a").Property("Next", Ref("b")),
+		Bean(Test_circular_struct{}).ID("b").Prototype().Property("Next", Ref("a")),
+	)
+
+	// action
+	ctx, e := Context().AllowCircularReferences().Build(beans...)
+
+	// assert
+	assert.Nil(t, ctx)
+	var ce *CircularDependencyError
+	require.True(t, errors.As(e, &ce))
+	assert.Equal(t, []string{"a", "Next", "b", "Next", "a"}, ce.Path)
+}
+
+func Test_AllowCircularReferences_concurrent(t *testing.T) {
+	// arrange
+	ctx, e := Context().AllowCircularReferences().Build(Beans(
+		Bean(Test_circular_struct{}).ID("a").Property("Next", Ref("b")),
+		Bean(Test_circular_struct{}).ID("b").Property("Next", Ref("a")),
+	)...)
+	require.Nil(t, e)
+	beans := make([]interface{}, 20)
+	errs := make([]error, 20)
+
+	// action
+	var wg sync.WaitGroup
+	for i := range beans {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if i%2 == 0 {
+				beans[i], errs[i] = ctx.GetBean("a")
+			} else {
+				beans[i], errs[i] = ctx.GetBean("b")
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	// assert
+	for i := range beans {
+		require.Nil(t, errs[i])
+		assert.True(t, beans[i].(*Test_circular_struct).wired)
+		assert.True(t, beans[i] == beans[i%2])
+	}
+}
+
+type Test_circular_getter struct {
+	got interface{}
+	e   error
+}
+
+type Test_circular_user struct {
+	Getter *Test_circular_getter
+	Next   *Test_circular_struct
+}
+
+var Test_circular_ctx ApplicationContextI
+
+func (s *Test_circular_getter) Init() {
+	s.got, s.e = Test_circular_ctx.GetBean("b")
+}
+
+func Test_AllowCircularReferences_initGetsBean(t *testing.T) {
+	// arrange
+	ctx, e := Context().AllowCircularReferences().Build(Beans(
+		Bean(Test_circular_getter{}).ID("a"),
+		Bean(Test_circular_user{}).ID("b").Property("Getter", Ref("a")).Property("Next", Ref("c")),
+		Bean(Test_circular_struct{}).ID("c").Property("Next", Ref("c")),
+	)...)
+	require.Nil(t, e)
+	Test_circular_ctx = ctx
+
+	// action
+	done := make(chan error, 1)
+	var a interface{}
+	go func() {
+		var e error
+		a, e = ctx.GetBean("a")
+		done <- e
+	}()
+
+	// assert
+	select {
+	case e := <-done:
+		require.Nil(t, e)
+	case <-time.After(time.Second):
+		require.FailNow(t, "GetBean(...) in the initializer is blocked")
+	}
+	getter := a.(*Test_circular_getter)
+	require.Nil(t, getter.e)
+	b, e := ctx.GetBean("b")
+	require.Nil(t, e)
+	assert.True(t, getter.got == b)
+	assert.True(t, b.(*Test_circular_user).Getter == getter)
+}
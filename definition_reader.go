@@ -0,0 +1,478 @@
+package gospring
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+type definitionKind int
+
+const (
+	definitionNull definitionKind = iota
+	definitionScalar
+	definitionObject
+	definitionArray
+)
+
+// definitionNode is a value parsed from a bean definition file. It keeps
+// where the value is in the file, so errors can tell users what to fix.
+type definitionNode struct {
+	kind   definitionKind
+	file   string
+	line   int
+	column int
+
+	// scalar is the text of a scalar, and isString is true if it's quoted
+	// as a string in the file.
+	scalar   string
+	isString bool
+
+	keys    []string
+	members []*definitionNode
+	items   []*definitionNode
+
+	// wrapper is true if the node is explicitly a wrapper like {"ref": ...},
+	// which is known by formats where it looks different from a map.
+	wrapper bool
+
+	// decode decodes the node into the value which target points to.
+	decode func(target interface{}) error
+}
+
+func (node *definitionNode) location() string {
+	if node.file == "" {
+		return fmt.Sprintf("line %d", node.line)
+	}
+	return fmt.Sprintf("%s:%d", node.file, node.line)
+}
+
+func (node *definitionNode) member(key string) *definitionNode {
+	for i, k := range node.keys {
+		if k == key {
+			return node.members[i]
+		}
+	}
+	return nil
+}
+
+// definitionError is an error at a node of a definition file.
+func definitionError(node *definitionNode, path string, format string, a ...interface{}) error {
+	return fmt.Errorf("%s: %s: %s", node.location(), path, fmt.Sprintf(format, a...))
+}
+
+const (
+	definitionRef      = "ref"
+	definitionBean     = "bean"
+	definitionProperty = "property"
+	definitionValue    = "value"
+)
+
+// definitionReader converts definition nodes into beans with types and
+// factories from a registry. The schema of a bean is
+//
+// {
+//     "id": "a_id",
+//     "type": "Astruct",          // or "chan": "int" with "buffer": 1
+//     "scope": "Singleton",       // Default, Singleton, Prototype, Request or a registered scope
+//     "init": "Init",
+//     "finalize": "Finalize",
+//     "lazy": false,
+//     "profiles": ["prod"],
+//     "factory": {"name": "NewAstruct", "args": [...values]},
+//     "properties": {"Name": value}
+// }
+//
+// A value is one of
+//
+//     {"ref": "b_id"}             // Ref("b_id")
+//     {"bean": {...}}             // an inner bean
+//     {"property": "server.port"} // Value("server.port")
+//     {"value": ...}              // a literal
+//     ...                         // a literal
+//
+// Literals are decoded into the type of the field or the argument. Strings
+// with placeholders are kept as strings to be resolved by the context, where
+// "\${" is a literal "${", i.e. "\\${" in JSON. A slice field takes an
+// array of values, and a map field takes an object whose keys are converted
+// into keys of the map. A map whose only key is "ref", "bean", "property" or
+// "value" is read as a wrapper, so such a literal map is written as
+// {"value": {"ref": "x"}}.
+type definitionReader struct {
+	registry TypeRegistryI
+
+	// explicitWrappers is true if only nodes marked as wrappers are wrappers.
+	explicitWrappers bool
+}
+
+func (reader *definitionReader) readBeans(root *definitionNode) ([]BeanI, error) {
+
+	if root.kind != definitionObject {
+		return nil, definitionError(root, "$", "an object with \"beans\" is expected")
+	}
+
+	for _, key := range root.keys {
+		if key != "beans" {
+			return nil, definitionError(root.member(key), "$."+key, "unknown field [%s]", key)
+		}
+	}
+
+	node := root.member("beans")
+	if node == nil {
+		return []BeanI{}, nil
+	}
+	if node.kind != definitionArray {
+		return nil, definitionError(node, "$.beans", "an array is expected")
+	}
+
+	beans := make([]BeanI, 0, len(node.items))
+	for i, item := range node.items {
+		bean, e := reader.readBean(item, fmt.Sprintf("$.beans[%d]", i))
+		if e != nil {
+			return nil, e
+		}
+		beans = append(beans, bean)
+	}
+
+	return beans, nil
+}
+
+func (reader *definitionReader) readBean(node *definitionNode, path string) (BeanI, error) {
+
+	if node.kind != definitionObject {
+		return nil, definitionError(node, path, "an object is expected")
+	}
+
+	for _, key := range node.keys {
+		switch key {
+		case "id", "type", "chan", "buffer", "scope", "init", "finalize",
+			"lazy", "profiles", "factory", "properties":
+		default:
+			return nil, definitionError(node.member(key), path+"."+key, "unknown field [%s]", key)
+		}
+	}
+
+	var bean StructBeanI
+	var tvpe reflect.Type
+
+	typeNode, chanNode := node.member("type"), node.member("chan")
+	switch {
+	case typeNode != nil && chanNode != nil:
+		return nil, definitionError(node, path, "\"type\" and \"chan\" can't be used together")
+	case typeNode != nil:
+		t, e := reader.readType(typeNode, path+".type")
+		if e != nil {
+			return nil, e
+		}
+		tvpe = t
+		bean = Bean(reflect.New(t).Elem().Interface())
+	case chanNode != nil:
+		t, e := reader.readType(chanNode, path+".chan")
+		if e != nil {
+			return nil, e
+		}
+		buffer := 0
+		if bufferNode := node.member("buffer"); bufferNode != nil {
+			if e := bufferNode.decode(&buffer); e != nil {
+				return nil, definitionError(bufferNode, path+".buffer", "%v", e)
+			}
+		}
+		bean = Chan(reflect.New(t).Elem().Interface(), buffer)
+		tvpe = bean.(BeanI).GetType()
+	default:
+		return nil, definitionError(node, path, "\"type\" or \"chan\" is required")
+	}
+
+	if node.member("buffer") != nil && chanNode == nil {
+		return nil, definitionError(node.member("buffer"), path+".buffer", "\"buffer\" is only for \"chan\"")
+	}
+
+	for _, key := range []string{"id", "init", "finalize"} {
+		n := node.member(key)
+		if n == nil {
+			continue
+		}
+		s, e := reader.readString(n, path+"."+key)
+		if e != nil {
+			return nil, e
+		}
+		switch key {
+		case "id":
+			bean.ID(s)
+		case "init":
+			bean.Init(s)
+		case "finalize":
+			bean.Finalize(s)
+		}
+	}
+
+	if n := node.member("scope"); n != nil {
+		s, e := reader.readString(n, path+".scope")
+		if e != nil {
+			return nil, e
+		}
+		switch {
+		case strings.EqualFold(s, string(Default)):
+		case strings.EqualFold(s, string(Singleton)):
+			bean.Singleton()
+		case strings.EqualFold(s, string(Prototype)):
+			bean.Prototype()
+		case strings.EqualFold(s, string(Request)):
+			bean.Scope(Request)
+		case s == "":
+			return nil, definitionError(n, path+".scope", "scope is empty")
+		default:
+			// a scope registered on the context
+			bean.Scope(Scope(s))
+		}
+	}
+
+	if n := node.member("lazy"); n != nil {
+		var lazy bool
+		if e := n.decode(&lazy); e != nil {
+			return nil, definitionError(n, path+".lazy", "%v", e)
+		}
+		if lazy {
+			bean.Lazy()
+		} else {
+			bean.Eager()
+		}
+	}
+
+	if n := node.member("profiles"); n != nil {
+		var profiles []string
+		if e := n.decode(&profiles); e != nil {
+			return nil, definitionError(n, path+".profiles", "%v", e)
+		}
+		bean.Profile(profiles...)
+	}
+
+	if n := node.member("factory"); n != nil {
+		if e := reader.readFactory(bean, n, path+".factory"); e != nil {
+			return nil, e
+		}
+	}
+
+	if n := node.member("properties"); n != nil {
+		if n.kind != definitionObject {
+			return nil, definitionError(n, path+".properties", "an object is expected")
+		}
+		for i, name := range n.keys {
+			p := n.members[i]
+			ppath := path + ".properties." + name
+
+			property, e := newPropertyPath(tvpe, name)
+			if e != nil {
+				return nil, definitionError(p, ppath, "%v", e)
+			}
+
+			values, e := reader.readValues(p, ppath, property.tvpe)
+			if e != nil {
+				return nil, e
+			}
+			bean.Property(name, values...)
+		}
+	}
+
+	return bean.(BeanI), nil
+}
+
+func (reader *definitionReader) readFactory(bean StructBeanI, node *definitionNode, path string) error {
+
+	if node.kind != definitionObject {
+		return definitionError(node, path, "an object is expected")
+	}
+
+	for _, key := range node.keys {
+		if key != "name" && key != "args" {
+			return definitionError(node.member(key), path+"."+key, "unknown field [%s]", key)
+		}
+	}
+
+	nameNode := node.member("name")
+	if nameNode == nil {
+		return definitionError(node, path, "\"name\" is required")
+	}
+	name, e := reader.readString(nameNode, path+".name")
+	if e != nil {
+		return e
+	}
+	fn, present := reader.registry.GetFactory(name)
+	if !present {
+		return definitionError(nameNode, path+".name", "unknown factory [%s]", name)
+	}
+	fnType := reflect.TypeOf(fn)
+	if fnType.Kind() != reflect.Func {
+		return definitionError(nameNode, path+".name", "factory [%s] isn't a function", name)
+	}
+
+	argvs := []interface{}{}
+	if argsNode := node.member("args"); argsNode != nil {
+		if argsNode.kind != definitionArray {
+			return definitionError(argsNode, path+".args", "an array is expected")
+		}
+		if len(argsNode.items) != fnType.NumIn() {
+			return definitionError(argsNode, path+".args", "factory [%s] needs [%d] arguments instead of [%d]",
+				name, fnType.NumIn(), len(argsNode.items))
+		}
+		for i, item := range argsNode.items {
+			argv, e := reader.readValue(item, fmt.Sprintf("%s.args[%d]", path, i), fnType.In(i))
+			if e != nil {
+				return e
+			}
+			argvs = append(argvs, argv)
+		}
+	}
+
+	bean.Factory(fn, argvs...)
+	return nil
+}
+
+// readValues reads values of a property with the type of the field.
+func (reader *definitionReader) readValues(node *definitionNode, path string, tvpe reflect.Type) ([]interface{}, error) {
+
+	if !reader.isWrapper(node, tvpe) {
+		switch {
+		case tvpe.Kind() == reflect.Slice && node.kind == definitionArray:
+			values := make([]interface{}, 0, len(node.items))
+			for i, item := range node.items {
+				value, e := reader.readValue(item, fmt.Sprintf("%s[%d]", path, i), tvpe.Elem())
+				if e != nil {
+					return nil, e
+				}
+				values = append(values, value)
+			}
+			return values, nil
+
+		case tvpe.Kind() == reflect.Map && node.kind == definitionObject:
+			values := make([]interface{}, 0, len(node.keys))
+			for i, k := range node.keys {
+				mpath := path + "." + k
+				key, e := convertString(k, tvpe.Key())
+				if e != nil {
+					return nil, definitionError(node.members[i], mpath, "%v", e)
+				}
+				value, e := reader.readValue(node.members[i], mpath, tvpe.Elem())
+				if e != nil {
+					return nil, e
+				}
+				values = append(values, Entry(key.Interface(), value))
+			}
+			return values, nil
+		}
+	}
+
+	value, e := reader.readValue(node, path, tvpe)
+	if e != nil {
+		return nil, e
+	}
+	return []interface{}{value}, nil
+}
+
+// readValue reads a value which is injected into the type.
+func (reader *definitionReader) readValue(node *definitionNode, path string, tvpe reflect.Type) (BeanI, error) {
+
+	if reader.isWrapper(node, tvpe) {
+		key := node.keys[0]
+		n := node.members[0]
+		if tvpe.Kind() == reflect.Map && !isWrapperMember(key, n) {
+			return nil, definitionError(node, path, "a map with the only key [%s] has to be written as {\"value\": {...}}", key)
+		}
+		switch key {
+		case definitionRef:
+			id, e := reader.readString(n, path+"."+key)
+			if e != nil {
+				return nil, e
+			}
+			return Ref(id).(BeanI), nil
+		case definitionBean:
+			return reader.readBean(n, path+"."+key)
+		case definitionProperty:
+			k, e := reader.readString(n, path+"."+key)
+			if e != nil {
+				return nil, e
+			}
+			return Value(k).(BeanI), nil
+		case definitionValue:
+			return reader.readLiteral(n, path+"."+key, tvpe)
+		}
+	}
+
+	return reader.readLiteral(node, path, tvpe)
+}
+
+func (reader *definitionReader) readLiteral(node *definitionNode, path string, tvpe reflect.Type) (BeanI, error) {
+
+	if node.kind == definitionNull {
+		return nil, definitionError(node, path, "null isn't supported")
+	}
+
+	// placeholders are resolved by the context
+	if node.kind == definitionScalar && node.isString && hasPlaceholder(node.scalar) {
+		return &valueBean{value: node.scalar}, nil
+	}
+
+	value := reflect.New(tvpe)
+	if e := node.decode(value.Interface()); e != nil {
+		return nil, definitionError(node, path, "can't decode into [%v]. Caused by: %v", tvpe, e)
+	}
+
+	if value.Elem().Kind() == reflect.Interface {
+		if value.Elem().IsNil() {
+			return nil, definitionError(node, path, "null isn't supported")
+		}
+		return &valueBean{value: value.Elem().Elem().Interface()}, nil
+	}
+
+	return &valueBean{value: value.Elem().Interface()}, nil
+}
+
+func (reader *definitionReader) readType(node *definitionNode, path string) (reflect.Type, error) {
+	name, e := reader.readString(node, path)
+	if e != nil {
+		return nil, e
+	}
+	tvpe, present := reader.registry.GetType(name)
+	if !present {
+		return nil, definitionError(node, path, "unknown type [%s]", name)
+	}
+	return tvpe, nil
+}
+
+func (reader *definitionReader) readString(node *definitionNode, path string) (string, error) {
+	if node.kind != definitionScalar {
+		return "", definitionError(node, path, "a string is expected")
+	}
+	return node.scalar, nil
+}
+
+// isWrapper checks whether the node is like {"ref": ...} rather than a
+// value of the type.
+func (reader *definitionReader) isWrapper(node *definitionNode, tvpe reflect.Type) bool {
+	if reader.explicitWrappers {
+		return node.wrapper
+	}
+	if node.kind != definitionObject || len(node.keys) != 1 {
+		return false
+	}
+	switch node.keys[0] {
+	case definitionRef, definitionBean, definitionProperty, definitionValue:
+		return true
+	default:
+		return false
+	}
+}
+
+// isWrapperMember checks whether the node fits the key of a wrapper, e.g.
+// {"ref": "x"}, rather than being a member of a literal map like
+// {"ref": {...}}.
+func isWrapperMember(key string, node *definitionNode) bool {
+	switch key {
+	case definitionRef, definitionProperty:
+		return node.kind == definitionScalar
+	case definitionBean:
+		return node.kind == definitionObject
+	default:
+		return true
+	}
+}
@@ -0,0 +1,261 @@
+package gospring
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"runtime"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// ExportJSON serializes definitions of beans into a JSON document with the
+// same schema as LoadJSON(...), so the effective wiring can be saved and
+// compared.
+//
+// Names of types and factories are looked up from the registry, which can
+// be nil. Types and factories which aren't registered are written with
+// their Go names, e.g. "main.Astruct", and can be loaded back only if
+// they are registered with these names. Initial values given to Bean(...)
+// and conditions like OnBean(...) or When(...) aren't exported.
+func ExportJSON(beans []BeanI, registry TypeRegistryI) ([]byte, error) {
+	document, e := exportDefinitions(beans, registry)
+	if e != nil {
+		return nil, e
+	}
+	return json.MarshalIndent(document, "", "    ")
+}
+
+// ExportYAML is the same as ExportJSON but writes a YAML document which
+// can be loaded by LoadYAML(...).
+func ExportYAML(beans []BeanI, registry TypeRegistryI) ([]byte, error) {
+	document, e := exportDefinitions(beans, registry)
+	if e != nil {
+		return nil, e
+	}
+	return yaml.Marshal(document)
+}
+
+type beansDocument struct {
+	Beans []*beanDocument `json:"beans" yaml:"beans"`
+}
+
+type beanDocument struct {
+	ID         string                 `json:"id,omitempty" yaml:"id,omitempty"`
+	Type       string                 `json:"type,omitempty" yaml:"type,omitempty"`
+	Chan       string                 `json:"chan,omitempty" yaml:"chan,omitempty"`
+	Buffer     int                    `json:"buffer,omitempty" yaml:"buffer,omitempty"`
+	Scope      string                 `json:"scope,omitempty" yaml:"scope,omitempty"`
+	Init       string                 `json:"init,omitempty" yaml:"init,omitempty"`
+	Finalize   string                 `json:"finalize,omitempty" yaml:"finalize,omitempty"`
+	Lazy       *bool                  `json:"lazy,omitempty" yaml:"lazy,omitempty"`
+	Profiles   []string               `json:"profiles,omitempty" yaml:"profiles,omitempty"`
+	Factory    *factoryDocument       `json:"factory,omitempty" yaml:"factory,omitempty"`
+	Properties map[string]interface{} `json:"properties,omitempty" yaml:"properties,omitempty"`
+}
+
+type factoryDocument struct {
+	Name string        `json:"name" yaml:"name"`
+	Args []interface{} `json:"args,omitempty" yaml:"args,omitempty"`
+}
+
+func exportDefinitions(beans []BeanI, registry TypeRegistryI) (*beansDocument, error) {
+
+	writer := definitionWriter{
+		registry: registry,
+	}
+
+	document := &beansDocument{
+		Beans: []*beanDocument{},
+	}
+
+	for i, bean := range beans {
+		// references are resolved by the context and aren't definitions
+		if _, ok := bean.(ReferenceBeanI); ok {
+			continue
+		}
+		definition, e := writer.writeBean(bean, fmt.Sprintf("$.beans[%d]", i))
+		if e != nil {
+			return nil, e
+		}
+		document.Beans = append(document.Beans, definition)
+	}
+
+	return document, nil
+}
+
+// definitionWriter converts beans into definitions which are read by
+// definitionReader.
+type definitionWriter struct {
+	registry TypeRegistryI
+}
+
+func (writer *definitionWriter) writeBean(bean BeanI, path string) (*beanDocument, error) {
+
+	sbean, ok := bean.(*structBean)
+	if !ok {
+		return nil, fmt.Errorf("%s: only beans created by Bean(...) or Chan(...) can be exported", path)
+	}
+
+	tvpe := sbean.GetType()
+	if tvpe == nil {
+		return nil, fmt.Errorf("%s: type is nil", path)
+	}
+
+	definition := &beanDocument{
+		Lazy:     sbean.GetLazy(),
+		Profiles: sbean.GetProfiles(),
+	}
+
+	if id := sbean.GetID(); id != nil {
+		definition.ID = *id
+	}
+
+	if buffer := sbean.GetBuffer(); buffer != nil {
+		definition.Chan = writer.typeName(tvpe.Elem())
+		definition.Buffer = *buffer
+	} else {
+		definition.Type = writer.typeName(tvpe)
+	}
+
+	if scope := sbean.GetScope(); scope != Default {
+		definition.Scope = string(scope)
+	}
+	if init := sbean.GetInit(); init != nil {
+		definition.Init = *init
+	}
+	if finalize := sbean.GetFinalize(); finalize != nil {
+		definition.Finalize = *finalize
+	}
+
+	if sbean.HasFactory() {
+		fn, argvs := sbean.GetFactory()
+		factory := &factoryDocument{
+			Name: writer.factoryName(fn),
+		}
+		for i, argv := range argvs {
+			arg, e := writer.writeValue(argv, fmt.Sprintf("%s.factory.args[%d]", path, i))
+			if e != nil {
+				return nil, e
+			}
+			factory.Args = append(factory.Args, arg)
+		}
+		definition.Factory = factory
+	}
+
+	for name, values := range sbean.GetProperties() {
+		ppath := path + ".properties." + name
+		property, e := writer.writeProperty(sbean, name, values, ppath)
+		if e != nil {
+			return nil, e
+		}
+		if definition.Properties == nil {
+			definition.Properties = make(map[string]interface{})
+		}
+		definition.Properties[name] = property
+	}
+
+	return definition, nil
+}
+
+// writeProperty writes values of a property in the way definitionReader
+// reads them with the type of the field.
+func (writer *definitionWriter) writeProperty(bean *structBean, name string, values []BeanI, path string) (interface{}, error) {
+
+	var fieldType reflect.Type
+	if property, e := newPropertyPath(bean.GetType(), name); e == nil {
+		fieldType = property.tvpe
+	}
+
+	switch {
+	case fieldType != nil && fieldType.Kind() == reflect.Slice:
+		items := make([]interface{}, 0, len(values))
+		for i, value := range values {
+			item, e := writer.writeValue(value, fmt.Sprintf("%s[%d]", path, i))
+			if e != nil {
+				return nil, e
+			}
+			items = append(items, item)
+		}
+		return items, nil
+
+	case len(bean.GetEntries(name)) > 0:
+		m := make(map[string]interface{})
+		for i, entry := range bean.GetEntries(name) {
+			if entry == nil {
+				return nil, fmt.Errorf("%s: the number [%d] value isn't created by Entry(...)", path, i)
+			}
+			key := fmt.Sprintf("%v", entry.GetKey())
+			if _, present := m[key]; present {
+				return nil, fmt.Errorf("%s: key [%s] is duplicated", path, key)
+			}
+			value, e := writer.writeValue(values[i], path+"."+key)
+			if e != nil {
+				return nil, e
+			}
+			m[key] = value
+		}
+		return m, nil
+
+	case len(values) != 1:
+		return nil, fmt.Errorf("%s: a property which isn't a slice needs one value instead of [%d]", path, len(values))
+
+	default:
+		return writer.writeValue(values[0], path)
+	}
+}
+
+func (writer *definitionWriter) writeValue(bean BeanI, path string) (interface{}, error) {
+
+	switch b := bean.(type) {
+	case *referenceBean:
+		return map[string]interface{}{definitionRef: b.id}, nil
+
+	case *propertyBean:
+		return map[string]interface{}{definitionProperty: b.GetKey()}, nil
+
+	case *structBean:
+		definition, e := writer.writeBean(b, path+"."+definitionBean)
+		if e != nil {
+			return nil, e
+		}
+		return map[string]interface{}{definitionBean: definition}, nil
+
+	case *valueBean:
+		if b.value == nil {
+			return nil, fmt.Errorf("%s: nil can't be exported", path)
+		}
+		// a literal which is an object or an array is wrapped, so it's
+		// neither taken as a wrapper nor split into values of a property
+		switch reflect.TypeOf(b.value).Kind() {
+		case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array, reflect.Ptr:
+			return map[string]interface{}{definitionValue: b.value}, nil
+		default:
+			return b.value, nil
+		}
+
+	default:
+		return nil, fmt.Errorf("%s: bean [%v] can't be exported", path, bean)
+	}
+}
+
+func (writer *definitionWriter) typeName(tvpe reflect.Type) string {
+	if writer.registry != nil {
+		if name, ok := writer.registry.GetTypeName(tvpe); ok {
+			return name
+		}
+	}
+	return tvpe.String()
+}
+
+func (writer *definitionWriter) factoryName(fn interface{}) string {
+	if writer.registry != nil {
+		if name, ok := writer.registry.GetFactoryName(fn); ok {
+			return name
+		}
+	}
+	if f := runtime.FuncForPC(reflect.ValueOf(fn).Pointer()); f != nil {
+		return f.Name()
+	}
+	return fmt.Sprintf("%T", fn)
+}
@@ -0,0 +1,194 @@
+package gospring
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Test_Export_struct1 struct {
+	Name    string
+	Timeout time.Duration
+	Next    *Test_Export_struct2
+	Inner   *Test_Export_struct2
+	Names   []string
+	Map     map[int]*Test_Export_struct2
+	Port    int
+	Chan    chan int
+	Labels  map[string]string
+	Tags    map[string]string
+}
+
+type Test_Export_struct2 struct {
+	Name string
+}
+
+func (s *Test_Export_struct2) Init() {}
+
+func NewTest_Export_struct2(name string) *Test_Export_struct2 {
+	return &Test_Export_struct2{
+		Name: name,
+	}
+}
+
+func NewTest_Export_labels() *map[string]string {
+	return &map[string]string{"a": "b"}
+}
+
+func newTest_Export_registry() TypeRegistryI {
+	return NewTypeRegistry().
+		Type("struct1", Test_Export_struct1{}).
+		Type("struct2", Test_Export_struct2{}).
+		Type("labels", map[string]string{}).
+		Factory("newStruct2", NewTest_Export_struct2).
+		Factory("newLabels", NewTest_Export_labels)
+}
+
+func newTest_Export_beans() []BeanI {
+	return Beans(
+		Bean(Test_Export_struct1{}).
+			ID("1").
+			Prototype().
+			Property("Name", "${name:one}").
+			Property("Timeout", time.Second).
+			Property("Next", Ref("2")).
+			Property("Inner", Bean(Test_Export_struct2{}).Property("Name", "inner")).
+			Property("Names", "a", "b").
+			Property("Map", Entry(1, Ref("2"))).
+			Property("Port", Value("port")).
+			Property("Chan", Ref("chan")).
+			Property("Labels", Ref("labels")).
+			Property("Tags", map[string]string{"ref": "x"}),
+		Bean(Test_Export_struct2{}).
+			ID("2").
+			Singleton().
+			Eager().
+			Init("Init").
+			Profile("default").
+			Factory(NewTest_Export_struct2, "two"),
+		Chan(0, 3).ID("chan"),
+		Bean(map[string]string{}).ID("labels").Factory(NewTest_Export_labels),
+		Ref("2"),
+	)
+}
+
+func Test_ExportJSON(t *testing.T) {
+	// arrange
+	beans := Beans(
+		Bean(Test_Export_struct2{}).
+			ID("2").
+			Singleton().
+			Init("Init").
+			Factory(NewTest_Export_struct2, "two"),
+	)
+
+	// action
+	data, e := ExportJSON(beans, newTest_Export_registry())
+
+	// assert
+	require.Nil(t, e)
+	assert.JSONEq(t, `{
+		"beans": [{
+			"id": "2",
+			"type": "struct2",
+			"scope": "Singleton",
+			"init": "Init",
+			"factory": {"name": "newStruct2", "args": ["two"]}
+		}]
+	}`, string(data))
+}
+
+func Test_ExportJSON_roundTrip(t *testing.T) {
+	// arrange
+	registry := newTest_Export_registry()
+	data, e := ExportJSON(newTest_Export_beans(), registry)
+	require.Nil(t, e)
+
+	// action
+	beans, e := LoadJSON(data, registry)
+
+	// assert
+	require.Nil(t, e, string(data))
+	assertTest_Export_beans(t, beans)
+}
+
+func Test_ExportYAML_roundTrip(t *testing.T) {
+	// arrange
+	registry := newTest_Export_registry()
+	data, e := ExportYAML(newTest_Export_beans(), registry)
+	require.Nil(t, e)
+
+	// action
+	beans, e := LoadYAML(data, registry)
+
+	// assert
+	require.Nil(t, e, string(data))
+	assertTest_Export_beans(t, beans)
+}
+
+func assertTest_Export_beans(t *testing.T, beans []BeanI) {
+	ctx, e := Context().
+		Profiles("default").
+		Properties(map[string]string{"port": "80"}).
+		Build(beans...)
+	require.Nil(t, e)
+
+	bean, e := ctx.GetBean("1")
+	require.Nil(t, e)
+	two, e := ctx.GetBean("2")
+	require.Nil(t, e)
+
+	b := bean.(*Test_Export_struct1)
+	assert.Equal(t, "one", b.Name)
+	assert.Equal(t, time.Second, b.Timeout)
+	assert.True(t, b.Next == two)
+	assert.Equal(t, "two", b.Next.Name)
+	assert.Equal(t, "inner", b.Inner.Name)
+	assert.Equal(t, []string{"a", "b"}, b.Names)
+	assert.True(t, b.Map[1] == two)
+	assert.Equal(t, 80, b.Port)
+	assert.Equal(t, 3, cap(b.Chan))
+	assert.Equal(t, map[string]string{"a": "b"}, b.Labels)
+	assert.Equal(t, map[string]string{"ref": "x"}, b.Tags)
+}
+
+func Test_ExportJSON_withoutRegistry(t *testing.T) {
+	// arrange
+	beans := Beans(
+		Bean(Test_Export_struct2{}).Factory(NewTest_Export_struct2, "two"),
+	)
+
+	// action
+	data, e := ExportJSON(beans, nil)
+
+	// assert
+	require.Nil(t, e)
+	assert.Contains(t, string(data), `"type": "gospring.Test_Export_struct2"`)
+	assert.Contains(t, string(data), `"name": "github.com/yarencheng/gospring.NewTest_Export_struct2"`)
+}
+
+func Test_ExportJSON_errors(t *testing.T) {
+	// arrange
+	cases := []struct {
+		beans    []BeanI
+		expected string
+	}{
+		{Beans(1), "$.beans[0]: only beans created by"},
+		{Beans(Bean(Test_Export_struct1{}).Property("Name", "a", "b")), "$.beans[0].properties.Name: a property which isn't a slice needs one value instead of [2]"},
+		{Beans(Bean(Test_Export_struct1{}).Property("Map", Entry(1, "a"), "b")), "$.beans[0].properties.Map: the number [1] value isn't created by Entry(...)"},
+		{Beans(Bean(Test_Export_struct1{}).Property("Inner", Bean(Test_Export_struct2{}).Property("Name", nil))), "$.beans[0].properties.Inner.bean.properties.Name: nil can't be exported"},
+	}
+
+	for _, c := range cases {
+		// action
+		data, e := ExportJSON(c.beans, nil)
+
+		// assert
+		assert.Nil(t, data)
+		if assert.NotNil(t, e) {
+			assert.Contains(t, e.Error(), c.expected)
+		}
+	}
+}
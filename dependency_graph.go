@@ -0,0 +1,480 @@
+package gospring
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// GraphFormat is a format of the dependency graph exported by
+// ApplicationContextI.ExportGraph(...).
+type GraphFormat string
+
+const (
+	// GraphDOT is the format of Graphviz.
+	GraphDOT GraphFormat = "dot"
+
+	// GraphMermaid is a flowchart of Mermaid.
+	GraphMermaid GraphFormat = "mermaid"
+
+	// GraphJSON is a JSON document like
+	//
+	// {
+	//     "nodes": [{"name": "a_id", "id": "a_id", "type": "main.Astruct", "scope": "Singleton"}],
+	//     "edges": [{"from": "a_id", "to": "b_id", "label": "Bfield"}]
+	// }
+	GraphJSON GraphFormat = "json"
+)
+
+// dependencyGraph is the graph of bean definitions. An edge points from a
+// bean to a bean it depends on through a field or a factory argument.
+// Literals and properties from the environment aren't nodes.
+type dependencyGraph struct {
+	nodes []*dependencyNode
+}
+
+type dependencyNode struct {
+	// name is unique in the graph. It's the ID of the bean, or a generated
+	// one like "anonymous#1" for a bean without ID.
+	name  string
+	bean  BeanI
+	scope Scope
+	tvpe  reflect.Type
+
+	// anonymous is true if the bean has no ID, and parent is true if the
+	// bean is defined in the parent context.
+	anonymous bool
+	parent    bool
+
+	edges []*dependencyEdge
+}
+
+type dependencyEdge struct {
+	from  *dependencyNode
+	to    *dependencyNode
+	label string
+
+	// argument is true if the edge is a factory argument, or false if it's
+	// a property
+	argument bool
+}
+
+// dependencyGraph builds the graph from top-level beans, whose references
+// are resolved already.
+func (ctx *applicationContext) dependencyGraph() *dependencyGraph {
+	builder := dependencyGraphBuilder{
+		ctx:      ctx,
+		graph:    &dependencyGraph{},
+		byBean:   make(map[BeanI]*dependencyNode),
+		byParent: make(map[string]*dependencyNode),
+	}
+	for _, bean := range ctx.beans {
+		builder.add(bean)
+	}
+	return builder.graph
+}
+
+type dependencyGraphBuilder struct {
+	ctx       *applicationContext
+	graph     *dependencyGraph
+	byBean    map[BeanI]*dependencyNode
+	byParent  map[string]*dependencyNode
+	anonymous int
+}
+
+// add adds the bean and beans it depends on, and returns the node of the
+// bean, or nil if the bean isn't a node.
+func (builder *dependencyGraphBuilder) add(bean BeanI) *dependencyNode {
+
+	switch b := bean.(type) {
+	case ReferenceBeanI:
+		if target := b.GetReference(); target != nil {
+			return builder.add(target)
+		}
+		return nil
+
+	case *parentBean:
+		if n, present := builder.byParent[b.id]; present {
+			return n
+		}
+		n := &dependencyNode{
+			name:   b.id,
+			bean:   b,
+			scope:  b.GetScope(),
+			tvpe:   b.GetType(),
+			parent: true,
+		}
+		builder.byParent[b.id] = n
+		builder.graph.nodes = append(builder.graph.nodes, n)
+		return n
+
+	case *structBean:
+		if n, present := builder.byBean[b]; present {
+			return n
+		}
+		n := &dependencyNode{
+			bean:  b,
+			scope: b.GetScope(),
+			tvpe:  b.GetType(),
+		}
+		if id := b.GetID(); id != nil {
+			n.name = *id
+		} else {
+			builder.anonymous++
+			n.name = fmt.Sprintf("anonymous#%d", builder.anonymous)
+			n.anonymous = true
+		}
+		builder.byBean[b] = n
+		builder.graph.nodes = append(builder.graph.nodes, n)
+
+		_, argvs := b.GetFactory()
+		for i, argv := range argvs {
+			builder.connect(n, argv, fmt.Sprintf("factory arg #%d", i), true)
+		}
+
+		properties := builder.ctx.getProperties(b)
+		names := make([]string, 0, len(properties))
+		for name := range properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			ps := properties[name]
+			entries := b.GetEntries(name)
+			for i, p := range ps {
+				label := name
+				switch {
+				case i < len(entries) && entries[i] != nil:
+					label = fmt.Sprintf("%s[%v]", name, entries[i].GetKey())
+				case len(ps) > 1:
+					label = fmt.Sprintf("%s[%d]", name, i)
+				}
+				builder.connect(n, p, label, false)
+			}
+		}
+		return n
+
+	default:
+		return nil
+	}
+}
+
+func (builder *dependencyGraphBuilder) connect(from *dependencyNode, bean BeanI, label string, argument bool) {
+	to := builder.add(bean)
+	if to == nil {
+		return
+	}
+	edge := &dependencyEdge{
+		from:     from,
+		to:       to,
+		label:    label,
+		argument: argument,
+	}
+	from.edges = append(from.edges, edge)
+}
+
+// findCycle returns the first cycle found by a depth-first search, which
+// visits each node and edge once. The cycle is like
+// ["a_id", "Bfield", "b_id", "factory arg #0", "a_id"], or nil if there
+// isn't any.
+func (g *dependencyGraph) findCycle() []string {
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	states := make(map[*dependencyNode]int, len(g.nodes))
+	path := make([]*dependencyEdge, 0)
+
+	var visit func(n *dependencyNode) []string
+	visit = func(n *dependencyNode) []string {
+		states[n] = visiting
+		for _, edge := range n.edges {
+			switch states[edge.to] {
+			case visiting:
+				path = append(path, edge)
+				start := len(path) - 1
+				for path[start].from != edge.to {
+					start--
+				}
+				cycle := make([]string, 0, 2*(len(path)-start)+1)
+				for _, e := range path[start:] {
+					cycle = append(cycle, e.from.name, e.label)
+				}
+				return append(cycle, edge.to.name)
+			case unvisited:
+				path = append(path, edge)
+				if cycle := visit(edge.to); cycle != nil {
+					return cycle
+				}
+				path = path[:len(path)-1]
+			}
+		}
+		states[n] = visited
+		return nil
+	}
+
+	for _, n := range g.nodes {
+		if states[n] == unvisited {
+			if cycle := visit(n); cycle != nil {
+				return cycle
+			}
+		}
+	}
+
+	return nil
+}
+
+// findCycleExcept is the same as findCycle() but ignores cycles whose edges
+// are all allowed. An edge is in a cycle if and only if both ends are in the
+// same strongly connected component, so each edge which isn't allowed is
+// checked against the components.
+func (g *dependencyGraph) findCycleExcept(allowed func(edge *dependencyEdge) bool) []string {
+
+	components := g.components()
+
+	for _, n := range g.nodes {
+		for _, edge := range n.edges {
+			if components[edge.to] != components[n] || allowed(edge) {
+				continue
+			}
+			cycle := []string{n.name, edge.label}
+			return append(cycle, shortestPath(edge.to, n, components)...)
+		}
+	}
+
+	return nil
+}
+
+// components returns the strongly connected component of each node, which
+// are found by Tarjan's algorithm.
+func (g *dependencyGraph) components() map[*dependencyNode]int {
+
+	index := make(map[*dependencyNode]int, len(g.nodes))
+	low := make(map[*dependencyNode]int, len(g.nodes))
+	onStack := make(map[*dependencyNode]bool, len(g.nodes))
+	stack := make([]*dependencyNode, 0)
+	components := make(map[*dependencyNode]int, len(g.nodes))
+	count := 0
+
+	var visit func(n *dependencyNode)
+	visit = func(n *dependencyNode) {
+		index[n] = len(index)
+		low[n] = index[n]
+		stack = append(stack, n)
+		onStack[n] = true
+
+		for _, edge := range n.edges {
+			if _, visited := index[edge.to]; !visited {
+				visit(edge.to)
+				if low[edge.to] < low[n] {
+					low[n] = low[edge.to]
+				}
+			} else if onStack[edge.to] && index[edge.to] < low[n] {
+				low[n] = index[edge.to]
+			}
+		}
+
+		if low[n] == index[n] {
+			for {
+				m := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[m] = false
+				components[m] = count
+				if m == n {
+					break
+				}
+			}
+			count++
+		}
+	}
+
+	for _, n := range g.nodes {
+		if _, visited := index[n]; !visited {
+			visit(n)
+		}
+	}
+
+	return components
+}
+
+// shortestPath returns the path like ["a_id", "Bfield", "b_id"] found by a
+// breadth-first search in the component of both nodes.
+func shortestPath(from, to *dependencyNode, components map[*dependencyNode]int) []string {
+
+	component := components[from]
+	via := map[*dependencyNode]*dependencyEdge{from: nil}
+	queue := []*dependencyNode{from}
+
+	for len(queue) > 0 && to != from {
+		n := queue[0]
+		queue = queue[1:]
+		for _, edge := range n.edges {
+			if _, visited := via[edge.to]; visited || components[edge.to] != component {
+				continue
+			}
+			via[edge.to] = edge
+			queue = append(queue, edge.to)
+		}
+		if _, found := via[to]; found {
+			break
+		}
+	}
+
+	path := []string{to.name}
+	for n := to; via[n] != nil; n = via[n].from {
+		path = append([]string{via[n].from.name, via[n].label}, path...)
+	}
+	return path
+}
+
+func (node *dependencyNode) typeName() string {
+	if node.tvpe == nil {
+		return ""
+	}
+	return node.tvpe.String()
+}
+
+func (ctx *applicationContext) ExportGraph(format GraphFormat) ([]byte, error) {
+	g := ctx.dependencyGraph()
+	switch format {
+	case GraphDOT:
+		return g.dot(), nil
+	case GraphMermaid:
+		return g.mermaid(), nil
+	case GraphJSON:
+		return g.json()
+	default:
+		return nil, fmt.Errorf("Unknown graph format [%v]", format)
+	}
+}
+
+func (g *dependencyGraph) dot() []byte {
+
+	quote := func(s string) string {
+		s = strings.Replace(s, `\`, `\\`, -1)
+		s = strings.Replace(s, `"`, `\"`, -1)
+		s = strings.Replace(s, "\n", `\n`, -1)
+		return `"` + s + `"`
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("digraph beans {\n")
+
+	for _, n := range g.nodes {
+		attrs := []string{
+			"label=" + quote(strings.Join([]string{n.name, n.typeName(), string(n.scope)}, "\n")),
+			"type=" + quote(n.typeName()),
+			"scope=" + quote(string(n.scope)),
+		}
+		if n.anonymous {
+			attrs = append(attrs, `anonymous="true"`)
+		}
+		if n.parent {
+			attrs = append(attrs, `parent="true"`, `style="dashed"`)
+		}
+		fmt.Fprintf(&buf, "    %s [%s];\n", quote(n.name), strings.Join(attrs, ", "))
+	}
+
+	for _, n := range g.nodes {
+		for _, edge := range n.edges {
+			fmt.Fprintf(&buf, "    %s -> %s [label=%s];\n", quote(n.name), quote(edge.to.name), quote(edge.label))
+		}
+	}
+
+	buf.WriteString("}\n")
+	return buf.Bytes()
+}
+
+func (g *dependencyGraph) mermaid() []byte {
+
+	// names may contain characters which Mermaid doesn't accept in IDs
+	ids := make(map[*dependencyNode]string)
+	for i, n := range g.nodes {
+		ids[n] = fmt.Sprintf("n%d", i)
+	}
+
+	quote := func(s string) string {
+		return `"` + strings.Replace(s, `"`, "#quot;", -1) + `"`
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("graph LR\n")
+
+	for _, n := range g.nodes {
+		label := quote(strings.Join([]string{n.name, n.typeName(), string(n.scope)}, "<br/>"))
+		if n.parent {
+			fmt.Fprintf(&buf, "    %s([%s])\n", ids[n], label)
+		} else {
+			fmt.Fprintf(&buf, "    %s[%s]\n", ids[n], label)
+		}
+	}
+
+	for _, n := range g.nodes {
+		for _, edge := range n.edges {
+			fmt.Fprintf(&buf, "    %s -->|%s| %s\n", ids[n], quote(edge.label), ids[edge.to])
+		}
+	}
+
+	return buf.Bytes()
+}
+
+type graphDocument struct {
+	Nodes []graphDocumentNode `json:"nodes"`
+	Edges []graphDocumentEdge `json:"edges"`
+}
+
+type graphDocumentNode struct {
+	Name      string `json:"name"`
+	ID        string `json:"id,omitempty"`
+	Type      string `json:"type"`
+	Scope     string `json:"scope"`
+	Anonymous bool   `json:"anonymous,omitempty"`
+	Parent    bool   `json:"parent,omitempty"`
+}
+
+type graphDocumentEdge struct {
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Label string `json:"label"`
+}
+
+func (g *dependencyGraph) json() ([]byte, error) {
+
+	document := graphDocument{
+		Nodes: []graphDocumentNode{},
+		Edges: []graphDocumentEdge{},
+	}
+
+	for _, n := range g.nodes {
+		node := graphDocumentNode{
+			Name:      n.name,
+			Type:      n.typeName(),
+			Scope:     string(n.scope),
+			Anonymous: n.anonymous,
+			Parent:    n.parent,
+		}
+		if !n.anonymous {
+			node.ID = n.name
+		}
+		document.Nodes = append(document.Nodes, node)
+	}
+
+	for _, n := range g.nodes {
+		for _, edge := range n.edges {
+			document.Edges = append(document.Edges, graphDocumentEdge{
+				From:  n.name,
+				To:    edge.to.name,
+				Label: edge.label,
+			})
+		}
+	}
+
+	return json.MarshalIndent(document, "", "    ")
+}
@@ -0,0 +1,253 @@
+package gospring
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Test_ExportGraph_struct struct {
+	Next  *Test_ExportGraph_struct
+	Inner *Test_ExportGraph_struct
+	List  []*Test_ExportGraph_struct
+	Map   map[string]*Test_ExportGraph_struct
+	Name  string
+}
+
+func NewTest_ExportGraph_struct(next *Test_ExportGraph_struct) *Test_ExportGraph_struct {
+	return &Test_ExportGraph_struct{
+		Next: next,
+	}
+}
+
+func Test_ExportGraph_dot(t *testing.T) {
+	// arrange
+	parent, e := NewApplicationContext(Beans(
+		Bean(Test_ExportGraph_struct{}).ID("p"),
+	)...)
+	require.Nil(t, e)
+
+	ctx, e := Context().Parent(parent).Build(Beans(
+		Bean(Test_ExportGraph_struct{}).
+			ID("a").
+			Prototype().
+			Property("Name", "a").
+			Property("Next", Ref("b")).
+			Property("Inner", Bean(Test_ExportGraph_struct{})).
+			Property("List", Ref("b"), Ref("p")).
+			Property("Map", Entry("x", Ref("b"))),
+		Bean(Test_ExportGraph_struct{}).
+			ID("b").
+			Singleton().
+			Factory(NewTest_ExportGraph_struct, Ref("p")),
+	)...)
+	require.Nil(t, e)
+
+	// action
+	data, e := ctx.ExportGraph(GraphDOT)
+
+	// assert
+	require.Nil(t, e)
+	assert.Equal(t, `digraph beans {
+    "a" [label="a\ngospring.Test_ExportGraph_struct\nPrototype", type="gospring.Test_ExportGraph_struct", scope="Prototype"];
+    "anonymous#1" [label="anonymous#1\ngospring.Test_ExportGraph_struct\nDefault", type="gospring.Test_ExportGraph_struct", scope="Default", anonymous="true"];
+    "b" [label="b\ngospring.Test_ExportGraph_struct\nSingleton", type="gospring.Test_ExportGraph_struct", scope="Singleton"];
+    "p" [label="p\ngospring.Test_ExportGraph_struct\nDefault", type="gospring.Test_ExportGraph_struct", scope="Default", parent="true", style="dashed"];
+    "a" -> "anonymous#1" [label="Inner"];
+    "a" -> "b" [label="List[0]"];
+    "a" -> "p" [label="List[1]"];
+    "a" -> "b" [label="Map[x]"];
+    "a" -> "b" [label="Next"];
+    "b" -> "p" [label="factory arg #0"];
+}
+`, string(data))
+}
+
+func Test_ExportGraph_mermaid(t *testing.T) {
+	// arrange
+	parent, e := NewApplicationContext(Beans(
+		Bean(Test_ExportGraph_struct{}).ID("p"),
+	)...)
+	require.Nil(t, e)
+
+	ctx, e := Context().Parent(parent).Build(Beans(
+		Bean(Test_ExportGraph_struct{}).
+			ID("a").
+			Prototype().
+			Property("Name", "a").
+			Property("Next", Ref("b")).
+			Property("Inner", Bean(Test_ExportGraph_struct{})).
+			Property("List", Ref("b"), Ref("p")).
+			Property("Map", Entry("x", Ref("b"))),
+		Bean(Test_ExportGraph_struct{}).
+			ID("b").
+			Singleton().
+			Factory(NewTest_ExportGraph_struct, Ref("p")),
+	)...)
+	require.Nil(t, e)
+
+	// action
+	data, e := ctx.ExportGraph(GraphMermaid)
+
+	// assert
+	require.Nil(t, e)
+	assert.Contains(t, string(data), "graph LR\n")
+	assert.Contains(t, string(data), `    n0["a<br/>gospring.Test_ExportGraph_struct<br/>Prototype"]`)
+	assert.Contains(t, string(data), `    n3(["p<br/>gospring.Test_ExportGraph_struct<br/>Default"])`)
+	assert.Contains(t, string(data), `    n2 -->|"factory arg #0"| n3`)
+}
+
+func Test_ExportGraph_json(t *testing.T) {
+	// arrange
+	parent, e := NewApplicationContext(Beans(
+		Bean(Test_ExportGraph_struct{}).ID("p"),
+	)...)
+	require.Nil(t, e)
+
+	ctx, e := Context().Parent(parent).Build(Beans(
+		Bean(Test_ExportGraph_struct{}).
+			ID("a").
+			Prototype().
+			Property("Name", "a").
+			Property("Next", Ref("b")).
+			Property("Inner", Bean(Test_ExportGraph_struct{})).
+			Property("List", Ref("b"), Ref("p")).
+			Property("Map", Entry("x", Ref("b"))),
+		Bean(Test_ExportGraph_struct{}).
+			ID("b").
+			Singleton().
+			Factory(NewTest_ExportGraph_struct, Ref("p")),
+	)...)
+	require.Nil(t, e)
+
+	// action
+	data, e := ctx.ExportGraph(GraphJSON)
+
+	// assert
+	require.Nil(t, e)
+	assert.JSONEq(t, `{
+		"nodes": [
+			{"name": "a", "id": "a", "type": "gospring.Test_ExportGraph_struct", "scope": "Prototype"},
+			{"name": "anonymous#1", "type": "gospring.Test_ExportGraph_struct", "scope": "Default", "anonymous": true},
+			{"name": "b", "id": "b", "type": "gospring.Test_ExportGraph_struct", "scope": "Singleton"},
+			{"name": "p", "id": "p", "type": "gospring.Test_ExportGraph_struct", "scope": "Default", "parent": true}
+		],
+		"edges": [
+			{"from": "a", "to": "anonymous#1", "label": "Inner"},
+			{"from": "a", "to": "b", "label": "List[0]"},
+			{"from": "a", "to": "p", "label": "List[1]"},
+			{"from": "a", "to": "b", "label": "Map[x]"},
+			{"from": "a", "to": "b", "label": "Next"},
+			{"from": "b", "to": "p", "label": "factory arg #0"}
+		]
+	}`, string(data))
+}
+
+func Test_ExportGraph_unknownFormat(t *testing.T) {
+	// arrange
+	parent, e := NewApplicationContext(Beans(
+		Bean(Test_ExportGraph_struct{}).ID("p"),
+	)...)
+	require.Nil(t, e)
+
+	ctx, e := Context().Parent(parent).Build(Beans(
+		Bean(Test_ExportGraph_struct{}).
+			ID("a").
+			Prototype().
+			Property("Name", "a").
+			Property("Next", Ref("b")).
+			Property("Inner", Bean(Test_ExportGraph_struct{})).
+			Property("List", Ref("b"), Ref("p")).
+			Property("Map", Entry("x", Ref("b"))),
+		Bean(Test_ExportGraph_struct{}).
+			ID("b").
+			Singleton().
+			Factory(NewTest_ExportGraph_struct, Ref("p")),
+	)...)
+	require.Nil(t, e)
+
+	// action
+	data, e := ctx.ExportGraph("aaa")
+
+	// assert
+	assert.Nil(t, data)
+	assert.NotNil(t, e)
+}
+
+func newTest_findCycle_graph(edges ...string) *dependencyGraph {
+	g := &dependencyGraph{}
+	nodes := make(map[string]*dependencyNode)
+	get := func(name string) *dependencyNode {
+		if n, present := nodes[name]; present {
+			return n
+		}
+		n := &dependencyNode{name: name}
+		nodes[name] = n
+		g.nodes = append(g.nodes, n)
+		return n
+	}
+	for i := 0; i+1 < len(edges); i += 2 {
+		from, to := get(edges[i]), get(edges[i+1])
+		from.edges = append(from.edges, &dependencyEdge{
+			from:  from,
+			to:    to,
+			label: from.name + to.name,
+		})
+	}
+	return g
+}
+
+func Test_findCycle_noCycle(t *testing.T) {
+	// arrange
+	// a -> b -> c, a -> c, d -> e -> f
+	g := newTest_findCycle_graph("a", "b", "b", "c", "a", "c", "d", "e", "e", "f")
+
+	// action
+	cycle := g.findCycle()
+
+	// assert
+	assert.Nil(t, cycle)
+}
+
+func Test_findCycle_self(t *testing.T) {
+	// arrange
+	g := newTest_findCycle_graph("a", "a")
+
+	// action
+	cycle := g.findCycle()
+
+	// assert
+	assert.Equal(t, []string{"a", "aa", "a"}, cycle)
+}
+
+func Test_findCycle_path(t *testing.T) {
+	// arrange
+	// x -> a -> b -> c -> a
+	g := newTest_findCycle_graph("x", "a", "a", "b", "b", "c", "c", "a")
+
+	// action
+	cycle := g.findCycle()
+
+	// assert
+	assert.Equal(t, []string{"a", "ab", "b", "bc", "c", "ca", "a"}, cycle)
+}
+
+func Test_findCycle_diamonds(t *testing.T) {
+	// arrange
+	// n0 -> n1 and n0 -> m1, n1 -> n2 and n1 -> m2, ...
+	// which has 2^1000 paths from n0
+	edges := []string{}
+	for i := 0; i < 1000; i++ {
+		from, next := fmt.Sprintf("n%d", i), fmt.Sprintf("n%d", i+1)
+		edges = append(edges, from, next, from, "m"+next[1:], "m"+next[1:], next)
+	}
+	g := newTest_findCycle_graph(edges...)
+
+	// action
+	cycle := g.findCycle()
+
+	// assert
+	assert.Nil(t, cycle)
+}
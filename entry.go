@@ -0,0 +1,14 @@
+package gospring
+
+type entry struct {
+	key   interface{}
+	value interface{}
+}
+
+func (e *entry) GetKey() interface{} {
+	return e.key
+}
+
+func (e *entry) GetEntryValue() interface{} {
+	return e.value
+}
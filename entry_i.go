@@ -0,0 +1,6 @@
+package gospring
+
+type EntryI interface {
+	GetKey() interface{}
+	GetEntryValue() interface{}
+}
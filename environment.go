@@ -0,0 +1,41 @@
+package gospring
+
+type environment struct {
+	sources []PropertySourceI
+}
+
+// NewEnvironment creates an EnvironmentI object. Sources in front have
+// higher precedence, e.g.
+//
+// NewEnvironment(
+//     ArgsSource(os.Args[1:]),
+//     EnvSource(),
+//     fileSource,
+// )
+func NewEnvironment(sources ...PropertySourceI) EnvironmentI {
+	return &environment{
+		sources: append([]PropertySourceI{}, sources...),
+	}
+}
+
+func (env *environment) GetName() string {
+	return "environment"
+}
+
+func (env *environment) GetProperty(key string) (string, bool) {
+	for _, source := range env.sources {
+		if value, present := source.GetProperty(key); present {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+func (env *environment) ContainsProperty(key string) bool {
+	_, present := env.GetProperty(key)
+	return present
+}
+
+func (env *environment) GetSources() []PropertySourceI {
+	return append([]PropertySourceI{}, env.sources...)
+}
@@ -0,0 +1,9 @@
+package gospring
+
+// EnvironmentI holds ordered property sources. A property is taken from
+// the first source which has it.
+type EnvironmentI interface {
+	PropertySourceI
+	ContainsProperty(key string) bool
+	GetSources() []PropertySourceI
+}
@@ -0,0 +1,149 @@
+package gospring
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Environment_precedence(t *testing.T) {
+	// arrange
+	env := NewEnvironment(
+		MapSource("1", map[string]string{"a": "1"}),
+		MapSource("2", map[string]string{"a": "2", "b": "2"}),
+	)
+
+	// action
+	a, _ := env.GetProperty("a")
+	b, _ := env.GetProperty("b")
+
+	// assert
+	assert.Equal(t, "1", a)
+	assert.Equal(t, "2", b)
+	assert.False(t, env.ContainsProperty("c"))
+	assert.Len(t, env.GetSources(), 2)
+}
+
+func Test_GetEnvironment_parent(t *testing.T) {
+	// arrange
+	parent, e := Context().Properties(map[string]string{"a": "parent", "b": "parent"}).Build()
+	require.Nil(t, e)
+	child, e := Context().Parent(parent).Properties(map[string]string{"a": "child"}).Build()
+	require.Nil(t, e)
+
+	// action
+	a, _ := child.GetEnvironment().GetProperty("a")
+	b, _ := child.GetEnvironment().GetProperty("b")
+
+	// assert
+	assert.Equal(t, "child", a)
+	assert.Equal(t, "parent", b)
+}
+
+func Test_Value(t *testing.T) {
+	// arrange
+	type beanStruct struct {
+		Host    string
+		Port    int
+		Debug   bool
+		Timeout time.Duration
+		Ports   []uint16
+		Names   map[string]string
+	}
+	beans := Beans(
+		Bean(beanStruct{}).
+			ID("1").
+			Property("Host", Value("server.host")).
+			Property("Port", Value("server.port")).
+			Property("Debug", Value("debug")).
+			Property("Timeout", Value("timeout")).
+			Property("Ports", Value("server.port"), Value("admin.port")).
+			Property("Names", Entry("host", Value("server.host"))),
+	)
+	ctx, e := Context().
+		PropertySources(
+			ArgsSource([]string{"--server.port=8080", "--debug"}),
+			MapSource("default", map[string]string{
+				"server.host": "localhost",
+				"server.port": "80",
+				"timeout":     "3s",
+				"admin.port":  "9090",
+			}),
+		).
+		Build(beans...)
+	require.Nil(t, e)
+
+	// action
+	bean, e := ctx.GetBean("1")
+
+	// assert
+	require.Nil(t, e)
+	b := bean.(*beanStruct)
+	assert.Equal(t, "localhost", b.Host)
+	assert.Equal(t, 8080, b.Port)
+	assert.True(t, b.Debug)
+	assert.Equal(t, 3*time.Second, b.Timeout)
+	assert.Equal(t, []uint16{8080, 9090}, b.Ports)
+	assert.Equal(t, map[string]string{"host": "localhost"}, b.Names)
+}
+
+func Test_Value_factoryArgument(t *testing.T) {
+	// arrange
+	type beanStruct struct {
+		Port int
+	}
+	beans := Beans(
+		Bean(beanStruct{}).ID("1").Factory(func(port int) *beanStruct {
+			return &beanStruct{Port: port}
+		}, Value("port")),
+	)
+	ctx, e := Context().Properties(map[string]string{"port": "8080"}).Build(beans...)
+	require.Nil(t, e)
+
+	// action
+	bean, e := ctx.GetBean("1")
+
+	// assert
+	require.Nil(t, e)
+	assert.Equal(t, 8080, bean.(*beanStruct).Port)
+}
+
+func Test_Value_notExist(t *testing.T) {
+	// arrange
+	type beanStruct struct {
+		Port int
+	}
+	beans := Beans(
+		Bean(beanStruct{}).ID("1").Property("Port", Value("port")),
+	)
+
+	// action
+	_, e := NewApplicationContext(beans...)
+
+	// assert
+	assert.NotNil(t, e)
+}
+
+func Test_Value_convertFailed(t *testing.T) {
+	// arrange
+	type beanStruct struct {
+		Port int
+	}
+	beans := Beans(
+		Bean(beanStruct{}).ID("1").Property("Port", Value("port")),
+	)
+	ctx, e := (&contextBuilder{
+		sources:        []PropertySourceI{MapSource("properties", map[string]string{"port": "aaa"})},
+		skipValidation: true,
+	}).Build(beans...)
+	require.Nil(t, e)
+
+	// action
+	bean, e := ctx.GetBean("1")
+
+	// assert
+	assert.Nil(t, bean)
+	assert.NotNil(t, e)
+}
@@ -0,0 +1,96 @@
+package gospring
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrBeanNotFound is wrapped by errors caused by an ID which doesn't refer
+// to any bean, including beans disabled by profiles or conditions.
+//
+// if errors.Is(e, ErrBeanNotFound) {
+//     ...
+// }
+var ErrBeanNotFound = errors.New("bean not found")
+
+// ErrNoRequestScope is wrapped by errors caused by getting a bean in the
+// Request scope without a context.Context returned by WithRequestScope(...).
+var ErrNoRequestScope = errors.New("no request scope")
+
+// DuplicateIDError is returned when more than one bean has the same ID.
+type DuplicateIDError struct {
+	ID string
+}
+
+func (e *DuplicateIDError) Error() string {
+	return fmt.Sprintf("ID [%v] already exist", e.ID)
+}
+
+// CircularDependencyError is one of the problems of a *ValidationError when
+// beans depend on each other. Path is the cycle of bean names and the fields or factory arguments
+// between them, e.g. ["a_id", "Bfield", "b_id", "factory arg #0", "a_id"].
+type CircularDependencyError struct {
+	Path []string
+}
+
+func (e *CircularDependencyError) Error() string {
+	return fmt.Sprintf("Detect dependency loop [%s]", strings.Join(e.Path, " -> "))
+}
+
+// ValidationError is returned when bean definitions can't be wired, e.g. a
+// property of a field which doesn't exist, a reference to an unknown bean or
+// a dependency loop. Problems are all of the errors found, each of which
+// tells where it is.
+type ValidationError struct {
+	Problems []error
+}
+
+func (e *ValidationError) Error() string {
+	lines := make([]string, 0, len(e.Problems)+1)
+	lines = append(lines, fmt.Sprintf("Found [%d] problems in bean definitions:", len(e.Problems)))
+	for _, problem := range e.Problems {
+		lines = append(lines, "    "+problem.Error())
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (e *ValidationError) Unwrap() []error {
+	return e.Problems
+}
+
+// BeanCreationError is returned when a bean can't be created. Field is
+// the field which can't be injected, or empty if the bean fails for other
+// reasons, e.g. an error from its factory. A failure of a dependency is
+// another BeanCreationError in Cause.
+//
+// Path is how the failure is reached from the bean asked by GetBean(...),
+// with the Go types involved, e.g.
+//
+// ["a_id[main.Astruct].Asingleton", "b_id[main.Bstruct].Name", "(value int)"]
+//
+// Error() reports the path and the cause of the innermost BeanCreationError,
+// so the whole chain can be read at a glance.
+type BeanCreationError struct {
+	BeanID string
+	Field  string
+	Path   []string
+	Cause  error
+}
+
+func (e *BeanCreationError) Error() string {
+	innermost := e
+	for {
+		var next *BeanCreationError
+		if !errors.As(innermost.Cause, &next) {
+			break
+		}
+		innermost = next
+	}
+	return fmt.Sprintf("Can't create bean [%v] at [%s]. Caused by: %v",
+		e.BeanID, strings.Join(innermost.Path, " -> "), innermost.Cause)
+}
+
+func (e *BeanCreationError) Unwrap() error {
+	return e.Cause
+}
@@ -0,0 +1,150 @@
+package gospring
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Test_errors_struct struct {
+	B *Test_errors_struct
+}
+
+func Test_ErrBeanNotFound(t *testing.T) {
+	// arrange
+	parent, e := NewApplicationContext()
+	require.Nil(t, e)
+	ctx, e := Context().
+		Parent(parent).
+		Build(Beans(Bean(Test_errors_struct{}).ID("1").Profile("aaa"))...)
+	require.Nil(t, e)
+
+	// action
+	_, e1 := ctx.GetBean("1")
+	_, e2 := ctx.GetBean("2")
+	_, e3 := NewApplicationContext(Beans(
+		Bean(Test_errors_struct{}).ID("1").Property("B", Ref("2")),
+	)...)
+
+	// assert
+	assert.True(t, errors.Is(e1, ErrBeanNotFound))
+	assert.True(t, errors.Is(e2, ErrBeanNotFound))
+	assert.True(t, errors.Is(e3, ErrBeanNotFound))
+}
+
+func Test_DuplicateIDError(t *testing.T) {
+	// arrange
+	beans := Beans(
+		Bean(Test_errors_struct{}).ID("1"),
+		Bean(Test_errors_struct{}).ID("1"),
+	)
+
+	// action
+	_, e := NewApplicationContext(beans...)
+
+	// assert
+	var de *DuplicateIDError
+	require.True(t, errors.As(e, &de))
+	assert.Equal(t, "1", de.ID)
+}
+
+func Test_CircularDependencyError(t *testing.T) {
+	// arrange
+	beans := Beans(
+		Bean(Test_errors_struct{}).ID("1").Property("B", Ref("2")),
+		Bean(Test_errors_struct{}).ID("2").Property("B", Ref("1")),
+	)
+
+	// action
+	_, e := NewApplicationContext(beans...)
+
+	// assert
+	var ce *CircularDependencyError
+	require.True(t, errors.As(e, &ce))
+	assert.Equal(t, []string{"1", "B", "2", "B", "1"}, ce.Path)
+}
+
+func Test_BeanCreationError_nested(t *testing.T) {
+	// arrange
+	cause := errors.New("failed")
+	fn := func() (*Test_errors_struct, error) {
+		return nil, cause
+	}
+	beans := Beans(
+		Bean(Test_errors_struct{}).ID("1").Property("B", Ref("2")),
+		Bean(Test_errors_struct{}).ID("2").Factory(fn),
+	)
+	ctx, e := NewApplicationContext(beans...)
+	require.Nil(t, e)
+
+	// action
+	_, e = ctx.GetBean("1")
+
+	// assert
+	assert.True(t, errors.Is(e, cause))
+	var outer *BeanCreationError
+	require.True(t, errors.As(e, &outer))
+	assert.Equal(t, "1", outer.BeanID)
+	assert.Equal(t, "B", outer.Field)
+	var inner *BeanCreationError
+	require.True(t, errors.As(outer.Cause, &inner))
+	assert.Equal(t, "2", inner.BeanID)
+	assert.Equal(t, "", inner.Field)
+}
+
+type Test_BeanCreationError_path_struct1 struct {
+	Asingleton *Test_BeanCreationError_path_struct2
+}
+
+type Test_BeanCreationError_path_struct2 struct {
+	Name  int
+	Names []int
+}
+
+func Test_BeanCreationError_path(t *testing.T) {
+	// arrange
+	beans := Beans(
+		Bean(Test_BeanCreationError_path_struct1{}).ID("a_id").Property("Asingleton", Ref("b_id")),
+		Bean(Test_BeanCreationError_path_struct2{}).ID("b_id").Property("Name", "aaa"),
+	)
+	ctx, e := (&contextBuilder{skipValidation: true}).Build(beans...)
+	require.Nil(t, e)
+
+	// action
+	_, e = ctx.GetBean("a_id")
+
+	// assert
+	var ce *BeanCreationError
+	require.True(t, errors.As(e, &ce))
+	assert.Equal(t, "a_id", ce.BeanID)
+	assert.Contains(t, e.Error(), "Can't create bean [a_id] at ["+
+		"a_id[gospring.Test_BeanCreationError_path_struct1].Asingleton -> "+
+		"b_id[gospring.Test_BeanCreationError_path_struct2].Name -> "+
+		"(value string)]. Caused by: ")
+}
+
+func Test_BeanCreationError_pathOfElementAndFactoryArgument(t *testing.T) {
+	// arrange
+	fn := func(b *Test_BeanCreationError_path_struct2) *Test_BeanCreationError_path_struct1 {
+		return &Test_BeanCreationError_path_struct1{Asingleton: b}
+	}
+	beans := Beans(
+		Bean(Test_BeanCreationError_path_struct1{}).ID("a_id").Factory(fn,
+			Bean(Test_BeanCreationError_path_struct2{}).Property("Names", 1, "aaa"),
+		),
+	)
+	ctx, e := (&contextBuilder{skipValidation: true}).Build(beans...)
+	require.Nil(t, e)
+
+	// action
+	_, e = ctx.GetBean("a_id")
+
+	// assert
+	require.NotNil(t, e)
+	assert.Contains(t, e.Error(), "Can't create bean [a_id] at ["+
+		"a_id[gospring.Test_BeanCreationError_path_struct1].factory arg #0 -> "+
+		"anonymous[gospring.Test_BeanCreationError_path_struct2].Names[1] -> "+
+		"(value string)]. Caused by: Bean [*string] can't be convert to [int]")
+}
@@ -0,0 +1,174 @@
+package gospring
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+)
+
+// LoadJSON creates beans from a JSON document like
+//
+// {
+//     "beans": [
+//         {
+//             "id": "a_id",
+//             "type": "Astruct",
+//             "properties": {
+//                 "IntValue": 123,
+//                 "Asingleton": {"ref": "b_id"}
+//             }
+//         },
+//         {"id": "b_id", "type": "Bstruct"}
+//     ]
+// }
+//
+// Names of types and factories are looked up from the registry.
+func LoadJSON(data []byte, registry TypeRegistryI) ([]BeanI, error) {
+	return loadJSON(data, "", registry)
+}
+
+// LoadJSONFile is the same as LoadJSON but reads the document from a file.
+func LoadJSONFile(path string, registry TypeRegistryI) ([]BeanI, error) {
+	data, e := ioutil.ReadFile(path)
+	if e != nil {
+		return nil, fmt.Errorf("Can't read file [%v]. Caused by: %w", path, e)
+	}
+	return loadJSON(data, path, registry)
+}
+
+func loadJSON(data []byte, file string, registry TypeRegistryI) ([]BeanI, error) {
+
+	parser := jsonParser{
+		data:    data,
+		file:    file,
+		decoder: json.NewDecoder(bytes.NewReader(data)),
+	}
+	parser.decoder.UseNumber()
+
+	root, e := parser.parse()
+	if e != nil {
+		return nil, e
+	}
+
+	if _, e := parser.decoder.Token(); e != io.EOF {
+		line, _ := parser.position(int(parser.decoder.InputOffset()))
+		return nil, parser.errorf(line, "unexpected data after the document")
+	}
+
+	reader := definitionReader{
+		registry: registry,
+	}
+	return reader.readBeans(root)
+}
+
+// jsonParser parses JSON into definition nodes with their positions.
+type jsonParser struct {
+	data    []byte
+	file    string
+	decoder *json.Decoder
+}
+
+func (parser *jsonParser) parse() (*definitionNode, error) {
+
+	start := parser.skip(int(parser.decoder.InputOffset()))
+	line, column := parser.position(start)
+
+	token, e := parser.decoder.Token()
+	if e != nil {
+		return nil, parser.errorf(line, "invalid JSON. Caused by: %v", e)
+	}
+
+	node := &definitionNode{
+		file:   parser.file,
+		line:   line,
+		column: column,
+	}
+
+	switch t := token.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			node.kind = definitionObject
+			for parser.decoder.More() {
+				keyLine, _ := parser.position(parser.skip(int(parser.decoder.InputOffset())))
+				keyToken, e := parser.decoder.Token()
+				if e != nil {
+					return nil, parser.errorf(keyLine, "invalid JSON. Caused by: %v", e)
+				}
+				key := keyToken.(string)
+				for _, k := range node.keys {
+					if k == key {
+						return nil, parser.errorf(keyLine, "duplicated key [%s]", key)
+					}
+				}
+				member, e := parser.parse()
+				if e != nil {
+					return nil, e
+				}
+				node.keys = append(node.keys, key)
+				node.members = append(node.members, member)
+			}
+		case '[':
+			node.kind = definitionArray
+			for parser.decoder.More() {
+				item, e := parser.parse()
+				if e != nil {
+					return nil, e
+				}
+				node.items = append(node.items, item)
+			}
+		}
+		// the closing delimiter
+		if _, e := parser.decoder.Token(); e != nil {
+			return nil, parser.errorf(line, "invalid JSON. Caused by: %v", e)
+		}
+	case nil:
+		node.kind = definitionNull
+	case string:
+		node.kind = definitionScalar
+		node.scalar = t
+		node.isString = true
+	case json.Number:
+		node.kind = definitionScalar
+		node.scalar = t.String()
+	case bool:
+		node.kind = definitionScalar
+		node.scalar = fmt.Sprintf("%v", t)
+	}
+
+	raw := parser.data[start:parser.decoder.InputOffset()]
+	node.decode = func(target interface{}) error {
+		return json.Unmarshal(raw, target)
+	}
+
+	return node, nil
+}
+
+// skip returns the offset of the next token after whitespaces, commas and
+// colons.
+func (parser *jsonParser) skip(offset int) int {
+	for offset < len(parser.data) {
+		switch parser.data[offset] {
+		case ' ', '\t', '\r', '\n', ',', ':':
+			offset++
+		default:
+			return offset
+		}
+	}
+	return offset
+}
+
+// position returns the line and the column of the offset. Both start
+// from 1.
+func (parser *jsonParser) position(offset int) (line int, column int) {
+	line = 1 + bytes.Count(parser.data[:offset], []byte("\n"))
+	column = offset - bytes.LastIndexByte(parser.data[:offset], '\n')
+	return line, column
+}
+
+func (parser *jsonParser) errorf(line int, format string, a ...interface{}) error {
+	node := &definitionNode{file: parser.file, line: line}
+	return fmt.Errorf("%s: %s", node.location(), fmt.Sprintf(format, a...))
+}
@@ -0,0 +1,204 @@
+package gospring
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Test_LoadJSON_struct1 struct {
+	Int      int
+	String   string
+	Duration time.Duration
+	Port     int
+	Host     string
+	Ref      *Test_LoadJSON_struct2
+	Inner    *Test_LoadJSON_struct2
+	List     []*Test_LoadJSON_struct2
+	Map      map[string]*Test_LoadJSON_struct2
+	IntMap   map[int]string
+	Labels   map[string]string
+	Strings  []string
+	Any      interface{}
+	C        chan int
+}
+
+type Test_LoadJSON_struct2 struct {
+	Name string
+}
+
+func NewTest_LoadJSON_struct2(name string, n int) *Test_LoadJSON_struct2 {
+	return &Test_LoadJSON_struct2{
+		Name: name + time.Duration(n).String(),
+	}
+}
+
+func newTest_LoadJSON_registry() TypeRegistryI {
+	return NewTypeRegistry().
+		Type("struct1", Test_LoadJSON_struct1{}).
+		Type("struct2", &Test_LoadJSON_struct2{}).
+		Factory("newStruct2", NewTest_LoadJSON_struct2)
+}
+
+func Test_LoadJSON(t *testing.T) {
+	// arrange
+	data := []byte(`{
+	"beans": [
+		{
+			"id": "1",
+			"type": "struct1",
+			"scope": "prototype",
+			"properties": {
+				"Int": 123,
+				"String": "abc",
+				"Duration": {"value": 1000},
+				"Port": "${port:80}",
+				"Host": {"property": "host"},
+				"Ref": {"ref": "2"},
+				"Inner": {"bean": {"type": "struct2", "properties": {"Name": "inner"}}},
+				"List": [{"ref": "2"}, {"ref": "3"}],
+				"Map": {"a": {"ref": "2"}},
+				"IntMap": {"1": "one"},
+				"Strings": ["x", "y"],
+				"Any": 1.5,
+				"C": {"ref": "4"}
+			}
+		},
+		{
+			"id": "2",
+			"type": "struct2",
+			"scope": "Singleton",
+			"properties": {"Name": "two"}
+		},
+		{
+			"id": "3",
+			"type": "struct2",
+			"factory": {"name": "newStruct2", "args": ["three", 1]}
+		},
+		{"id": "4", "chan": "int", "buffer": 1}
+	]
+}`)
+	beans, e := LoadJSON(data, newTest_LoadJSON_registry())
+	require.Nil(t, e)
+	ctx, e := Context().Properties(map[string]string{"host": "localhost"}).Build(beans...)
+	require.Nil(t, e)
+
+	// action
+	bean, e := ctx.GetBean("1")
+
+	// assert
+	require.Nil(t, e)
+	b := bean.(*Test_LoadJSON_struct1)
+	two, _ := ctx.GetBean("2")
+	assert.Equal(t, 123, b.Int)
+	assert.Equal(t, "abc", b.String)
+	assert.Equal(t, time.Microsecond, b.Duration)
+	assert.Equal(t, 80, b.Port)
+	assert.Equal(t, "localhost", b.Host)
+	assert.True(t, b.Ref == two)
+	assert.Equal(t, "inner", b.Inner.Name)
+	require.Len(t, b.List, 2)
+	assert.True(t, b.List[0] == two)
+	assert.Equal(t, "three1ns", b.List[1].Name)
+	assert.True(t, b.Map["a"] == two)
+	assert.Equal(t, map[int]string{1: "one"}, b.IntMap)
+	assert.Equal(t, []string{"x", "y"}, b.Strings)
+	assert.Equal(t, 1.5, *b.Any.(*float64))
+	assert.Equal(t, 1, cap(b.C))
+}
+
+func Test_LoadJSON_mapLikeWrapper(t *testing.T) {
+	// arrange
+	data := []byte(`{
+	"beans": [
+		{"id": "1", "type": "struct1", "properties": {"Labels": {"value": {"ref": "x"}}}},
+		{"id": "2", "type": "struct1", "properties": {"Labels": {"ref": "3"}}}
+	]
+}`)
+	beans, e := LoadJSON(data, newTest_LoadJSON_registry())
+	require.Nil(t, e)
+	beans = append(beans, Beans(
+		Bean(map[string]string{}).ID("3").Factory(func() *map[string]string {
+			return &map[string]string{"a": "b"}
+		}),
+	)...)
+	ctx, e := NewApplicationContext(beans...)
+	require.Nil(t, e)
+
+	// action
+	bean1, e1 := ctx.GetBean("1")
+	bean2, e2 := ctx.GetBean("2")
+
+	// assert
+	require.Nil(t, e1)
+	require.Nil(t, e2)
+	assert.Equal(t, map[string]string{"ref": "x"}, bean1.(*Test_LoadJSON_struct1).Labels)
+	assert.Equal(t, map[string]string{"a": "b"}, bean2.(*Test_LoadJSON_struct1).Labels)
+}
+
+func Test_LoadJSON_errors(t *testing.T) {
+	// arrange
+	cases := []struct {
+		data     string
+		expected string
+	}{
+		{`[]`, "line 1: $"},
+		{`{"aaa": 1}`, "line 1: $.aaa"},
+		{"{\"beans\": [\n{\"id\": \"1\", \"type\": \"unknown\"}]}", "line 2: $.beans[0].type: unknown type [unknown]"},
+		{"{\"beans\": [\n{\"type\": \"struct2\",\n\"properties\": {\n\"Aaa\": 1}}]}", "line 4: $.beans[0].properties.Aaa: type"},
+		{"{\"beans\": [\n{\"type\": \"struct2\",\n\"properties\": {\n\"Name\": 1}}]}", "line 4: $.beans[0].properties.Name: can't decode"},
+		{"{\"beans\": [\n{\"type\": \"struct2\", \"aaa\": 1}]}", "line 2: $.beans[0].aaa: unknown field"},
+		{"{\"beans\": [\n{\"type\": \"struct2\", \"scope\": \"\"}]}", "line 2: $.beans[0].scope: scope is empty"},
+		{"{\"beans\": [\n{\"type\": \"struct2\",\n\"factory\": {\"name\": \"aaa\"}}]}", "line 3: $.beans[0].factory.name: unknown factory"},
+		{"{\"beans\": [\n{\"type\": \"struct2\",\n\"factory\": {\"name\": \"newStruct2\", \"args\": [1]}}]}", "line 3: $.beans[0].factory.args: factory"},
+		{"{\"beans\": [\n{\"id\": \"1\"}]}", "line 2: $.beans[0]: \"type\" or \"chan\" is required"},
+		{"{\"beans\": [\n{\"type\": \"struct2\", \"properties\": {\"Name\": null}}]}", "line 2: $.beans[0].properties.Name: null"},
+		{"{\"beans\": [\n{\"type\": \"struct1\",\n\"properties\": {\"Labels\": {\"ref\": {\"a\": \"b\"}}}}]}", "line 3: $.beans[0].properties.Labels: a map with the only key [ref] has to be written as {\"value\": {...}}"},
+		{"{\"beans\": [\n{\"type\": }]}", "line 2: invalid JSON"},
+		{"{\"beans\": []} 1", "unexpected data"},
+		{"{\"beans\": [], \"beans\": []}", "duplicated key"},
+	}
+
+	for _, c := range cases {
+		// action
+		beans, e := LoadJSON([]byte(c.data), newTest_LoadJSON_registry())
+
+		// assert
+		assert.Nil(t, beans, c.data)
+		if assert.NotNil(t, e, c.data) {
+			assert.Contains(t, e.Error(), c.expected, c.data)
+		}
+	}
+}
+
+func Test_LoadJSONFile(t *testing.T) {
+	// arrange
+	file, e := ioutil.TempFile("", "gospring")
+	require.Nil(t, e)
+	defer os.Remove(file.Name())
+	file.WriteString("{\"beans\": [\n{\"id\": \"1\", \"type\": \"aaa\"}]}")
+	file.Close()
+
+	// action
+	beans, e := LoadJSONFile(file.Name(), newTest_LoadJSON_registry())
+
+	// assert
+	assert.Nil(t, beans)
+	require.NotNil(t, e)
+	assert.Contains(t, e.Error(), file.Name()+":2:")
+}
+
+func Test_LoadJSONFile_notExist(t *testing.T) {
+	// arrange
+
+	// action
+	beans, e := LoadJSONFile("/not/exist", newTest_LoadJSON_registry())
+
+	// assert
+	assert.Nil(t, beans)
+	assert.NotNil(t, e)
+}
@@ -0,0 +1,81 @@
+package gospring
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// parentBean is a bean defined in the parent context. The instance is
+// always acquired from the parent, so the child context never caches or
+// finalizes it.
+type parentBean struct {
+	id     string
+	parent ApplicationContextI
+}
+
+// definition returns the bean definition inside the parent context, or nil
+// if the parent isn't created by this package.
+func (bean *parentBean) definition() BeanI {
+	if p, ok := bean.parent.(*applicationContext); ok {
+		if def, present := p.lookupBean(bean.id); present {
+			return def
+		}
+	}
+	return nil
+}
+
+func (bean *parentBean) GetValue() (*reflect.Value, error) {
+	return bean.getValue(context.Background())
+}
+
+// getValue gets the instance from the parent with the context, which may
+// hold a request scope.
+func (bean *parentBean) getValue(c context.Context) (*reflect.Value, error) {
+	i, e := bean.parent.GetBeanContext(c, bean.id)
+	if e != nil {
+		return nil, fmt.Errorf("Can't get bean [%v] from parent context. Caused by: %w", bean.id, e)
+	}
+	value := reflect.ValueOf(i)
+	return &value, nil
+}
+
+func (bean *parentBean) GetID() *string {
+	return &bean.id
+}
+
+// GetScope returns Singleton if the definition is unknown, so that the
+// instance is never copied into non-pointer fields.
+func (bean *parentBean) GetScope() Scope {
+	if def := bean.definition(); def != nil {
+		return def.GetScope()
+	}
+	return Singleton
+}
+
+func (bean *parentBean) GetFactory() (interface{}, []BeanI) {
+	return nil, nil
+}
+
+func (bean *parentBean) GetFinalize() *string {
+	return nil
+}
+
+func (bean *parentBean) GetInit() *string {
+	return nil
+}
+
+func (bean *parentBean) GetProperty(name string) []BeanI {
+	return nil
+}
+
+func (bean *parentBean) GetProperties() map[string][]BeanI {
+	return map[string][]BeanI{}
+}
+
+func (bean *parentBean) GetType() reflect.Type {
+	if def := bean.definition(); def != nil {
+		return def.GetType()
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package gospring
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	placeholderPrefix    = "${"
+	placeholderSuffix    = "}"
+	placeholderSeparator = ":"
+	placeholderEscape    = "\\"
+)
+
+// hasPlaceholder is true for escaped prefixes as well, which are unescaped
+// by resolvePlaceholders(...).
+func hasPlaceholder(s string) bool {
+	return strings.Contains(s, placeholderPrefix)
+}
+
+// isEscaped checks whether the placeholder prefix at i is escaped.
+func isEscaped(s string, i int) bool {
+	return strings.HasSuffix(s[:i], placeholderEscape)
+}
+
+// resolvePlaceholders replaces placeholders like "${key}" or
+// "${key:default}" in s with properties from the source. Placeholders can
+// be nested, e.g. "${db.${env}.url:${db.url}}", and properties can contain
+// placeholders as well. An escaped prefix "\${" is a literal "${", e.g.
+// "\${key}" is "${key}".
+func resolvePlaceholders(s string, source PropertySourceI) (string, error) {
+	return resolvePlaceholdersWith(s, source, map[string]bool{})
+}
+
+// visiting are keys being resolved, which are used to detect loops like
+// a=${b} and b=${a}.
+func resolvePlaceholdersWith(s string, source PropertySourceI, visiting map[string]bool) (string, error) {
+
+	var result strings.Builder
+
+	for {
+		start := strings.Index(s, placeholderPrefix)
+		if start < 0 {
+			result.WriteString(s)
+			return result.String(), nil
+		}
+
+		if isEscaped(s, start) {
+			result.WriteString(s[:start-len(placeholderEscape)])
+			result.WriteString(placeholderPrefix)
+			s = s[start+len(placeholderPrefix):]
+			continue
+		}
+
+		end := findPlaceholderEnd(s, start+len(placeholderPrefix))
+		if end < 0 {
+			return "", fmt.Errorf("Placeholder in [%v] isn't closed", s)
+		}
+
+		result.WriteString(s[:start])
+
+		inner := s[start+len(placeholderPrefix) : end]
+		rawKey, rawDefault, hasDefault := splitPlaceholder(inner)
+
+		key, e := resolvePlaceholdersWith(rawKey, source, visiting)
+		if e != nil {
+			return "", e
+		}
+
+		if visiting[key] {
+			return "", fmt.Errorf("Placeholder [%v] refers to itself", key)
+		}
+
+		if value, present := source.GetProperty(key); present {
+			visiting[key] = true
+			value, e = resolvePlaceholdersWith(value, source, visiting)
+			delete(visiting, key)
+			if e != nil {
+				return "", fmt.Errorf("Can't resolve property [%v]. Caused by: %w", key, e)
+			}
+			result.WriteString(value)
+		} else if hasDefault {
+			value, e := resolvePlaceholdersWith(rawDefault, source, visiting)
+			if e != nil {
+				return "", e
+			}
+			result.WriteString(value)
+		} else {
+			return "", fmt.Errorf("Can't resolve placeholder [%v]", key)
+		}
+
+		s = s[end+len(placeholderSuffix):]
+	}
+}
+
+// findPlaceholderEnd returns the index of the "}" which closes the
+// placeholder starting before from, or -1.
+func findPlaceholderEnd(s string, from int) int {
+	depth := 0
+	for i := from; i < len(s); i++ {
+		switch {
+		case strings.HasPrefix(s[i:], placeholderPrefix) && !isEscaped(s, i):
+			depth++
+			i += len(placeholderPrefix) - 1
+		case strings.HasPrefix(s[i:], placeholderSuffix):
+			if depth == 0 {
+				return i
+			}
+			depth--
+		}
+	}
+	return -1
+}
+
+// splitPlaceholder splits "key:default" by the first separator outside
+// nested placeholders.
+func splitPlaceholder(inner string) (key string, def string, hasDefault bool) {
+	depth := 0
+	for i := 0; i < len(inner); i++ {
+		switch {
+		case strings.HasPrefix(inner[i:], placeholderPrefix) && !isEscaped(inner, i):
+			depth++
+			i += len(placeholderPrefix) - 1
+		case strings.HasPrefix(inner[i:], placeholderSuffix):
+			depth--
+		case depth == 0 && strings.HasPrefix(inner[i:], placeholderSeparator):
+			return inner[:i], inner[i+len(placeholderSeparator):], true
+		}
+	}
+	return inner, "", false
+}
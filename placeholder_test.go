@@ -0,0 +1,191 @@
+package gospring
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_resolvePlaceholders(t *testing.T) {
+	// arrange
+	source := MapSource("", map[string]string{
+		"env":         "prod",
+		"db.prod.url": "prod-url",
+		"db.url":      "default-url",
+		"host":        "${server}:${port:80}",
+		"server":      "localhost",
+	})
+	cases := []struct {
+		s        string
+		expected string
+	}{
+		{"abc", "abc"},
+		{"${env}", "prod"},
+		{"a-${env}-b", "a-prod-b"},
+		{"${port:8080}", "8080"},
+		{"${port:}", ""},
+		{"${db.${env}.url}", "prod-url"},
+		{"${db.${env}.user:${db.url}}", "default-url"},
+		{"${a:${b:${c:deep}}}", "deep"},
+		{"${host}", "localhost:80"},
+		{"${env}${env}", "prodprod"},
+		{`\${env}`, "${env}"},
+		{`a-\${env}-${env}`, "a-${env}-prod"},
+		{`${port:\${port}}`, "${port}"},
+		{`\${unknown`, "${unknown"},
+	}
+
+	for _, c := range cases {
+		// action
+		actual, e := resolvePlaceholders(c.s, source)
+
+		// assert
+		if assert.Nil(t, e, c.s) {
+			assert.Equal(t, c.expected, actual, c.s)
+		}
+	}
+}
+
+func Test_resolvePlaceholders_failed(t *testing.T) {
+	// arrange
+	source := MapSource("", map[string]string{
+		"a": "${b}",
+		"b": "${a}",
+	})
+	cases := []string{
+		"${c}",
+		"${c",
+		"${a}",
+		"${${c}:1}",
+	}
+
+	for _, c := range cases {
+		// action
+		_, e := resolvePlaceholders(c, source)
+
+		// assert
+		assert.NotNil(t, e, c)
+	}
+}
+
+func Test_placeholder_property(t *testing.T) {
+	// arrange
+	type beanStruct struct {
+		Port    int
+		Debug   bool
+		Timeout time.Duration
+		URL     string
+	}
+	beans := Beans(
+		Bean(beanStruct{}).
+			ID("1").
+			Property("Port", "${server.port:8080}").
+			Property("Debug", "${debug:false}").
+			Property("Timeout", "${timeout}").
+			Property("URL", "http://${server.host}:${server.port:8080}/"),
+	)
+	ctx, e := Context().
+		Properties(map[string]string{
+			"server.host": "localhost",
+			"timeout":     "5s",
+			"debug":       "true",
+		}).
+		Build(beans...)
+	require.Nil(t, e)
+
+	// action
+	bean, e := ctx.GetBean("1")
+
+	// assert
+	require.Nil(t, e)
+	b := bean.(*beanStruct)
+	assert.Equal(t, 8080, b.Port)
+	assert.True(t, b.Debug)
+	assert.Equal(t, 5*time.Second, b.Timeout)
+	assert.Equal(t, "http://localhost:8080/", b.URL)
+}
+
+func Test_placeholder_escaped(t *testing.T) {
+	// arrange
+	type beanStruct struct {
+		Template string
+	}
+	beans := Beans(
+		Bean(beanStruct{}).ID("1").Property("Template", `Hello \${name}`),
+	)
+	ctx, e := NewApplicationContext(beans...)
+	require.Nil(t, e)
+
+	// action
+	bean, e := ctx.GetBean("1")
+
+	// assert
+	require.Nil(t, e)
+	assert.Equal(t, "Hello ${name}", bean.(*beanStruct).Template)
+}
+
+func Test_placeholder_factoryArgument(t *testing.T) {
+	// arrange
+	type beanStruct struct {
+		Port int
+	}
+	beans := Beans(
+		Bean(beanStruct{}).ID("1").Factory(func(port int) *beanStruct {
+			return &beanStruct{Port: port}
+		}, "${port}"),
+	)
+	ctx, e := Context().Properties(map[string]string{"port": "8080"}).Build(beans...)
+	require.Nil(t, e)
+
+	// action
+	bean, e := ctx.GetBean("1")
+
+	// assert
+	require.Nil(t, e)
+	assert.Equal(t, 8080, bean.(*beanStruct).Port)
+}
+
+func Test_placeholder_unresolvable(t *testing.T) {
+	// arrange
+	type beanStruct struct {
+		Port int
+	}
+	beans := Beans(
+		Bean(beanStruct{}).ID("a_id").Property("Port", "${server.port}"),
+	)
+
+	// action
+	_, e := NewApplicationContext(beans...)
+
+	// assert
+	require.NotNil(t, e)
+	assert.Contains(t, e.Error(), "[a_id]")
+	assert.Contains(t, e.Error(), "[Port]")
+	assert.Contains(t, e.Error(), "server.port")
+}
+
+func Test_placeholder_valueProperty(t *testing.T) {
+	// arrange
+	type beanStruct struct {
+		URL string
+	}
+	beans := Beans(
+		Bean(beanStruct{}).ID("1").Property("URL", Value("url")),
+	)
+	ctx, e := Context().
+		Properties(map[string]string{
+			"url":  "http://${host}/",
+			"host": "localhost",
+		}).
+		Build(beans...)
+	require.Nil(t, e)
+
+	// action
+	bean, e := ctx.GetBean("1")
+
+	// assert
+	require.Nil(t, e)
+	assert.Equal(t, "http://localhost/", bean.(*beanStruct).URL)
+}
@@ -0,0 +1,416 @@
+package gospring
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// DefaultResetFunc is the name of the method which resets an idle object
+// before it's borrowed again, unless another one is given by Reset(...).
+const DefaultResetFunc string = "Reset"
+
+// DefaultPoolTimeout is how long Get(...) of a pool scope waits for an
+// object to be released, unless another one is given by Timeout(...).
+const DefaultPoolTimeout time.Duration = time.Minute
+
+type poolScope struct {
+	min, max  int
+	resetName *string
+	timeout   time.Duration
+
+	// lock guards all below, and cond is signaled when objects are
+	// released or dropped
+	lock  sync.Mutex
+	cond  *sync.Cond
+	pools map[string]*objectPool
+
+	// borrowed are objects which aren't released yet, and their pools
+	borrowed map[interface{}]*objectPool
+	closed   bool
+
+	// orphans are objects dropped before their destruction callbacks are
+	// registered, which are finalized once they are registered
+	orphans map[interface{}]bool
+}
+
+// objectPool holds objects of one bean.
+type objectPool struct {
+	idle []interface{}
+
+	// total counts idle and borrowed objects, and ones being created or
+	// reset
+	total  int
+	warmed bool
+	// removed is set by Remove(...), then released objects are dropped
+	removed bool
+
+	// callbacks are destruction callbacks of idle and borrowed objects
+	callbacks map[interface{}]func() error
+}
+
+// NewPoolScope creates a scope where objects of a bean are borrowed from a
+// pool which holds at most max objects, and min objects are created at
+// once when the pool is used the first time. Get(...) blocks while all
+// objects of the pool are borrowed, until one is released or it times out
+// after DefaultPoolTimeout. max less than 1 means there is no limit.
+//
+// scope := NewPoolScope(2, 8)
+// ctx, e := Context().
+//     Scope("pool", scope).
+//     Build(
+//         Bean(Buffer{}).ID("buffer").Scope("pool"),
+//     )
+// buffer, e := ctx.GetBean("buffer")
+// defer scope.Release(buffer)
+//
+// An idle object is reset by its Reset() method, if there is, before it's
+// borrowed again. Objects have to be comparable, e.g. pointers, so they
+// can be released. Pool-scoped beans can't be injected into other beans,
+// since nobody would release them.
+func NewPoolScope(min, max int) PoolScopeI {
+	if max > 0 && min > max {
+		min = max
+	}
+	scope := &poolScope{
+		min:      min,
+		max:      max,
+		timeout:  DefaultPoolTimeout,
+		pools:    make(map[string]*objectPool),
+		borrowed: make(map[interface{}]*objectPool),
+		orphans:  make(map[interface{}]bool),
+	}
+	scope.cond = sync.NewCond(&scope.lock)
+	return scope
+}
+
+func (scope *poolScope) Reset(name string) PoolScopeI {
+	scope.resetName = &name
+	return scope
+}
+
+func (scope *poolScope) Timeout(timeout time.Duration) PoolScopeI {
+	scope.timeout = timeout
+	return scope
+}
+
+// Get doesn't hold the lock while creating or resetting objects, which may
+// depend on other beans in the scope. A failed reset drops the object, and
+// Get tries the next one.
+func (scope *poolScope) Get(name string, create func() (interface{}, error)) (interface{}, error) {
+
+	scope.lock.Lock()
+	defer scope.lock.Unlock()
+
+	// the timer wakes up the goroutine waiting for objects when it times out
+	var deadline time.Time
+	var timer *time.Timer
+	defer func() {
+		if timer != nil {
+			timer.Stop()
+		}
+	}()
+
+	for {
+		if scope.closed {
+			return nil, fmt.Errorf("The pool scope is finalized")
+		}
+
+		pool, present := scope.pools[name]
+		if !present {
+			pool = &objectPool{callbacks: make(map[interface{}]func() error)}
+			scope.pools[name] = pool
+		}
+
+		if n := len(pool.idle); n > 0 {
+			object := pool.idle[n-1]
+			pool.idle = pool.idle[:n-1]
+
+			scope.lock.Unlock()
+			e := scope.callResetFunc(object)
+			scope.lock.Lock()
+
+			if e == nil && !scope.closed {
+				scope.borrowed[object] = pool
+				return object, nil
+			}
+
+			// errors of finalizers of dropped objects are ignored
+			callback := scope.drop(pool, object)
+			if callback != nil {
+				scope.lock.Unlock()
+				callback()
+				scope.lock.Lock()
+			}
+			continue
+		}
+
+		if scope.max < 1 || pool.total < scope.max {
+			return scope.fill(pool, create)
+		}
+
+		if scope.timeout > 0 {
+			if timer == nil {
+				deadline = time.Now().Add(scope.timeout)
+				timer = time.AfterFunc(scope.timeout, func() {
+					scope.lock.Lock()
+					scope.cond.Broadcast()
+					scope.lock.Unlock()
+				})
+			} else if !time.Now().Before(deadline) {
+				return nil, fmt.Errorf("Timed out after [%v] waiting for an object of [%v] in the pool scope",
+					scope.timeout, name)
+			}
+		}
+
+		scope.cond.Wait()
+	}
+}
+
+// fill creates an object to be borrowed, and more idle ones if the pool is
+// used the first time. It's called with the lock, which is released while
+// creating objects.
+func (scope *poolScope) fill(pool *objectPool, create func() (interface{}, error)) (interface{}, error) {
+
+	n := 1
+	if !pool.warmed {
+		pool.warmed = true
+		if scope.min > n {
+			n = scope.min
+		}
+	}
+	pool.total += n
+
+	scope.lock.Unlock()
+	var objects []interface{}
+	var e error
+	for i := 0; i < n && e == nil; i++ {
+		var object interface{}
+		if object, e = create(); e != nil {
+			break
+		}
+		if object == nil || !reflect.TypeOf(object).Comparable() {
+			e = fmt.Errorf("Object [%v] with type [%T] isn't comparable", object, object)
+			break
+		}
+		objects = append(objects, object)
+	}
+	scope.lock.Lock()
+
+	pool.total -= n - len(objects)
+	scope.cond.Broadcast()
+
+	if scope.closed {
+		// objects are finalized when their callbacks are registered
+		pool.total -= len(objects)
+		for _, object := range objects {
+			scope.orphans[object] = true
+		}
+		return nil, fmt.Errorf("The pool scope is finalized")
+	}
+
+	for _, object := range objects {
+		pool.callbacks[object] = nil
+	}
+
+	idle := objects
+	if e == nil {
+		scope.borrowed[objects[0]] = pool
+		idle = objects[1:]
+	}
+
+	// the pool may be removed while creating objects
+	if pool.removed {
+		for _, object := range idle {
+			scope.drop(pool, object)
+		}
+	} else {
+		pool.idle = append(pool.idle, idle...)
+	}
+
+	if e != nil {
+		return nil, e
+	}
+	return objects[0], nil
+}
+
+// drop removes the object from the pool, and returns its callback. The
+// object is an orphan if its callback isn't registered yet.
+func (scope *poolScope) drop(pool *objectPool, object interface{}) func() error {
+	callback := pool.callbacks[object]
+	if callback == nil {
+		scope.orphans[object] = true
+	}
+	delete(pool.callbacks, object)
+	pool.total--
+	scope.cond.Broadcast()
+	return callback
+}
+
+func (scope *poolScope) callResetFunc(object interface{}) error {
+
+	name := DefaultResetFunc
+	if scope.resetName != nil {
+		name = *scope.resetName
+	}
+
+	method := reflect.ValueOf(object).MethodByName(name)
+	if !method.IsValid() {
+		if scope.resetName != nil {
+			return fmt.Errorf("Can't get reset function [%v]", name)
+		}
+		return nil // donothing
+	}
+	if method.Type().NumIn() != 0 {
+		return fmt.Errorf("Function [%v] shouldn't have arguments", name)
+	}
+
+	rv := method.Call([]reflect.Value{})
+	switch len(rv) {
+	case 0:
+		return nil
+	case 1:
+		if rv[0].Type() != reflect.TypeOf((*error)(nil)).Elem() {
+			return fmt.Errorf(
+				"Function [%v] returns 1 unexpected value [%v] with type [%v]. ",
+				name,
+				rv[0].Interface(),
+				rv[0].Type(),
+			)
+		}
+		if rv[0].IsNil() {
+			return nil
+		}
+		return fmt.Errorf("Function [%v] return an error. Caused by: %w", name, rv[0].Interface().(error))
+	default:
+		return fmt.Errorf("Function [%v] returns [%v] values", name, len(rv))
+	}
+}
+
+// Remove drops the pool of the bean, and returns nil since there isn't one
+// object of the name. Idle objects are finalized at once, since nobody else
+// gets them, and borrowed ones are finalized when they are released. Errors
+// of finalizers of idle objects are ignored.
+func (scope *poolScope) Remove(name string) interface{} {
+
+	scope.lock.Lock()
+	pool, present := scope.pools[name]
+	if !present {
+		scope.lock.Unlock()
+		return nil
+	}
+	pool.removed = true
+	delete(scope.pools, name)
+
+	var callbacks []func() error
+	for _, object := range pool.idle {
+		if callback := scope.drop(pool, object); callback != nil {
+			callbacks = append(callbacks, callback)
+		}
+	}
+	pool.idle = nil
+	scope.cond.Broadcast()
+	scope.lock.Unlock()
+
+	for _, callback := range callbacks {
+		callback()
+	}
+	return nil
+}
+
+// RegisterDestructionCallback calls the callback at once if the object is
+// dropped already, e.g. the scope is finalized or the pool is removed, and
+// ignores objects which aren't created by the scope.
+func (scope *poolScope) RegisterDestructionCallback(name string, object interface{}, callback func() error) error {
+
+	if object == nil || !reflect.TypeOf(object).Comparable() {
+		return nil
+	}
+
+	scope.lock.Lock()
+	if scope.orphans[object] {
+		delete(scope.orphans, object)
+		scope.lock.Unlock()
+		return callback()
+	}
+
+	// a borrowed object may be of a removed pool
+	pool, present := scope.borrowed[object]
+	if !present {
+		pool, present = scope.pools[name]
+	}
+	if present {
+		if _, owned := pool.callbacks[object]; owned {
+			pool.callbacks[object] = callback
+		}
+	}
+	scope.lock.Unlock()
+	return nil
+}
+
+// Release gives the object back to its pool, or finalizes it if the scope
+// is finalized or the pool is removed.
+func (scope *poolScope) Release(object interface{}) error {
+
+	if object == nil || !reflect.TypeOf(object).Comparable() {
+		return fmt.Errorf("Object [%v] isn't borrowed from the pool scope", object)
+	}
+
+	scope.lock.Lock()
+	pool, present := scope.borrowed[object]
+	if !present {
+		scope.lock.Unlock()
+		return fmt.Errorf("Object [%v] isn't borrowed from the pool scope", object)
+	}
+	delete(scope.borrowed, object)
+
+	if scope.closed || pool.removed {
+		callback := scope.drop(pool, object)
+		scope.lock.Unlock()
+
+		if callback != nil {
+			if e := callback(); e != nil {
+				return fmt.Errorf("Can't finalize object [%v] in the pool scope. Caused by: %w", object, e)
+			}
+		}
+		return nil
+	}
+
+	pool.idle = append(pool.idle, object)
+	scope.cond.Broadcast()
+	scope.lock.Unlock()
+	return nil
+}
+
+// Finalize calls finalizers of all idle objects even if some fail, and
+// returns the first error. Get(...) fails after the scope is finalized.
+func (scope *poolScope) Finalize() error {
+
+	scope.lock.Lock()
+	if scope.closed {
+		scope.lock.Unlock()
+		return nil
+	}
+	scope.closed = true
+
+	var callbacks []func() error
+	for _, pool := range scope.pools {
+		for _, object := range pool.idle {
+			if callback := scope.drop(pool, object); callback != nil {
+				callbacks = append(callbacks, callback)
+			}
+		}
+		pool.idle = nil
+	}
+	scope.cond.Broadcast()
+	scope.lock.Unlock()
+
+	var first error
+	for _, callback := range callbacks {
+		if e := callback(); e != nil && first == nil {
+			first = fmt.Errorf("Can't finalize an object in the pool scope. Caused by: %w", e)
+		}
+	}
+	return first
+}
@@ -0,0 +1,25 @@
+package gospring
+
+import "time"
+
+// PoolScopeI is a scope whose objects are borrowed from bounded pools, one
+// pool for each bean, and are given back by Release(...).
+type PoolScopeI interface {
+	ScopeI
+
+	// Reset replaces the name of the method which is called before an
+	// object is borrowed again. It's DefaultResetFunc by default.
+	Reset(name string) PoolScopeI
+
+	// Timeout replaces how long Get(...) waits for an object to be
+	// released. It's DefaultPoolTimeout by default, and there is no limit
+	// if it's not positive.
+	Timeout(timeout time.Duration) PoolScopeI
+
+	// Release gives the object back to its pool.
+	Release(object interface{}) error
+
+	// Finalize finalizes idle objects and closes the scope. Objects borrowed
+	// already are finalized when they are released.
+	Finalize() error
+}
@@ -0,0 +1,324 @@
+package gospring
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Test_pool_struct struct {
+	Name      string
+	Buffer    []byte
+	resets    int
+	finalized bool
+}
+
+var Test_pool_created int32
+
+func newTest_pool_struct() *Test_pool_struct {
+	atomic.AddInt32(&Test_pool_created, 1)
+	return &Test_pool_struct{}
+}
+
+func (s *Test_pool_struct) Reset() {
+	s.resets++
+	s.Buffer = s.Buffer[:0]
+}
+
+func (s *Test_pool_struct) Clear() error {
+	return errors.New("failed")
+}
+
+func (s *Test_pool_struct) Finalize() {
+	s.finalized = true
+}
+
+func Test_PoolScope(t *testing.T) {
+	// arrange
+	scope := NewPoolScope(0, 2)
+	atomic.StoreInt32(&Test_pool_created, 0)
+	ctx, e := Context().Scope("pool", scope).Build(Beans(
+		Bean(Test_pool_struct{}).ID("1").Scope("pool").Factory(newTest_pool_struct).Property("Name", "a"),
+	)...)
+	require.Nil(t, e)
+	bean1, e := ctx.GetBean("1")
+	require.Nil(t, e)
+	bean1.(*Test_pool_struct).Buffer = append(bean1.(*Test_pool_struct).Buffer, 'x')
+
+	// action
+	er := scope.Release(bean1)
+	bean2, e2 := ctx.GetBean("1")
+	bean3, e3 := ctx.GetBean("1")
+
+	// assert
+	require.Nil(t, er)
+	require.Nil(t, e2)
+	require.Nil(t, e3)
+	assert.True(t, bean1 == bean2)
+	assert.False(t, bean1 == bean3)
+	assert.Equal(t, 1, bean2.(*Test_pool_struct).resets)
+	assert.Empty(t, bean2.(*Test_pool_struct).Buffer)
+	assert.Equal(t, 0, bean3.(*Test_pool_struct).resets)
+	assert.Equal(t, "a", bean3.(*Test_pool_struct).Name)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&Test_pool_created))
+}
+
+func Test_PoolScope_min(t *testing.T) {
+	// arrange
+	atomic.StoreInt32(&Test_pool_created, 0)
+	ctx, e := Context().Scope("pool", NewPoolScope(3, 5)).Build(Beans(
+		Bean(Test_pool_struct{}).ID("1").Scope("pool").Factory(newTest_pool_struct).Property("Name", "a"),
+	)...)
+	require.Nil(t, e)
+
+	// action
+	_, e1 := ctx.GetBean("1")
+	created := atomic.LoadInt32(&Test_pool_created)
+	_, e2 := ctx.GetBean("1")
+	_, e3 := ctx.GetBean("1")
+	_, e4 := ctx.GetBean("1")
+
+	// assert
+	require.Nil(t, e1)
+	require.Nil(t, e2)
+	require.Nil(t, e3)
+	require.Nil(t, e4)
+	assert.Equal(t, int32(3), created)
+	assert.Equal(t, int32(4), atomic.LoadInt32(&Test_pool_created))
+}
+
+func Test_PoolScope_max(t *testing.T) {
+	// arrange
+	scope := NewPoolScope(0, 1)
+	atomic.StoreInt32(&Test_pool_created, 0)
+	ctx, e := Context().Scope("pool", scope).Build(Beans(
+		Bean(Test_pool_struct{}).ID("1").Scope("pool").Factory(newTest_pool_struct).Property("Name", "a"),
+	)...)
+	require.Nil(t, e)
+	bean1, e := ctx.GetBean("1")
+	require.Nil(t, e)
+	got := make(chan interface{})
+
+	// action
+	go func() {
+		bean, _ := ctx.GetBean("1")
+		got <- bean
+	}()
+	var blocked bool
+	select {
+	case <-got:
+	case <-time.After(50 * time.Millisecond):
+		blocked = true
+	}
+	er := scope.Release(bean1)
+	bean2 := <-got
+
+	// assert
+	require.Nil(t, er)
+	assert.True(t, blocked)
+	assert.True(t, bean1 == bean2)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&Test_pool_created))
+}
+
+func Test_PoolScope_timeout(t *testing.T) {
+	// arrange
+	scope := NewPoolScope(0, 1).Timeout(10 * time.Millisecond)
+	ctx, e := Context().Scope("pool", scope).Build(Beans(
+		Bean(Test_pool_struct{}).ID("1").Scope("pool"),
+	)...)
+	require.Nil(t, e)
+	bean1, e := ctx.GetBean("1")
+	require.Nil(t, e)
+
+	// action
+	bean2, e2 := ctx.GetBean("1")
+	er := scope.Release(bean1)
+	bean3, e3 := ctx.GetBean("1")
+
+	// assert
+	assert.Nil(t, bean2)
+	require.NotNil(t, e2)
+	assert.Contains(t, e2.Error(), "Timed out after [10ms] waiting for an object of [1]")
+	require.Nil(t, er)
+	require.Nil(t, e3)
+	assert.True(t, bean1 == bean3)
+}
+
+func Test_PoolScope_resetFailed(t *testing.T) {
+	// arrange
+	scope := NewPoolScope(0, 1).Reset("Clear")
+	atomic.StoreInt32(&Test_pool_created, 0)
+	ctx, e := Context().Scope("pool", scope).Build(Beans(
+		Bean(Test_pool_struct{}).ID("1").Scope("pool").Factory(newTest_pool_struct).Property("Name", "a"),
+	)...)
+	require.Nil(t, e)
+	bean1, e := ctx.GetBean("1")
+	require.Nil(t, e)
+	require.Nil(t, scope.Release(bean1))
+
+	// action
+	bean2, e := ctx.GetBean("1")
+
+	// assert
+	require.Nil(t, e)
+	assert.False(t, bean1 == bean2)
+	assert.True(t, bean1.(*Test_pool_struct).finalized)
+	assert.False(t, bean2.(*Test_pool_struct).finalized)
+}
+
+func Test_PoolScope_releaseUnknown(t *testing.T) {
+	// arrange
+	scope := NewPoolScope(0, 1)
+	atomic.StoreInt32(&Test_pool_created, 0)
+	ctx, e := Context().Scope("pool", scope).Build(Beans(
+		Bean(Test_pool_struct{}).ID("1").Scope("pool").Factory(newTest_pool_struct).Property("Name", "a"),
+	)...)
+	require.Nil(t, e)
+	bean, e := ctx.GetBean("1")
+	require.Nil(t, e)
+	require.Nil(t, scope.Release(bean))
+
+	// action
+	e1 := scope.Release(bean)
+	e2 := scope.Release(&Test_pool_struct{})
+	e3 := scope.Release(nil)
+
+	// assert
+	assert.NotNil(t, e1)
+	assert.NotNil(t, e2)
+	assert.NotNil(t, e3)
+}
+
+func Test_PoolScope_remove(t *testing.T) {
+	// arrange
+	scope := NewPoolScope(0, 2)
+	ctx, e := Context().Scope("pool", scope).Build(Beans(
+		Bean(Test_pool_struct{}).ID("1").Scope("pool"),
+	)...)
+	require.Nil(t, e)
+	bean1, e := ctx.GetBean("1")
+	require.Nil(t, e)
+	bean2, e := ctx.GetBean("1")
+	require.Nil(t, e)
+	require.Nil(t, scope.Release(bean2))
+
+	// action
+	removed := scope.Remove("1")
+	finalized := bean1.(*Test_pool_struct).finalized
+	er := scope.Release(bean1)
+
+	// assert
+	require.Nil(t, er)
+	assert.Nil(t, removed)
+	assert.True(t, bean2.(*Test_pool_struct).finalized, "an idle object is finalized when the pool is removed")
+	assert.False(t, finalized)
+	assert.True(t, bean1.(*Test_pool_struct).finalized)
+}
+
+func Test_PoolScope_removedWhileCreating(t *testing.T) {
+	// arrange
+	scope := NewPoolScope(2, 2)
+	var created []*Test_pool_struct
+	ctx, e := Context().Scope("pool", scope).Build(Beans(
+		Bean(Test_pool_struct{}).ID("1").Scope("pool").Factory(func() *Test_pool_struct {
+			if len(created) == 1 {
+				scope.Remove("1")
+			}
+			created = append(created, &Test_pool_struct{})
+			return created[len(created)-1]
+		}),
+	)...)
+	require.Nil(t, e)
+
+	// action
+	bean1, e := ctx.GetBean("1")
+	finalized := bean1.(*Test_pool_struct).finalized
+	er := scope.Release(bean1)
+
+	// assert
+	require.Nil(t, e)
+	require.Nil(t, er)
+	require.Len(t, created, 2)
+	assert.True(t, bean1 == created[0])
+	assert.True(t, created[1].finalized, "an idle object of the removed pool is finalized")
+	assert.False(t, finalized)
+	assert.True(t, created[0].finalized)
+}
+
+func Test_PoolScope_Finalize(t *testing.T) {
+	// arrange
+	scope := NewPoolScope(2, 2)
+	atomic.StoreInt32(&Test_pool_created, 0)
+	ctx, e := Context().Scope("pool", scope).Build(Beans(
+		Bean(Test_pool_struct{}).ID("1").Scope("pool").Factory(newTest_pool_struct).Property("Name", "a"),
+	)...)
+	require.Nil(t, e)
+	bean1, e := ctx.GetBean("1")
+	require.Nil(t, e)
+	bean2, e := ctx.GetBean("1")
+	require.Nil(t, e)
+	require.Nil(t, scope.Release(bean1))
+
+	// action
+	e = ctx.Finalize()
+	finalized := bean2.(*Test_pool_struct).finalized
+	er := scope.Release(bean2)
+	_, eg := ctx.GetBean("1")
+
+	// assert
+	require.Nil(t, e)
+	require.Nil(t, er)
+	assert.NotNil(t, eg)
+	assert.True(t, bean1.(*Test_pool_struct).finalized)
+	assert.False(t, finalized, "a borrowed object is finalized when it's released")
+	assert.True(t, bean2.(*Test_pool_struct).finalized)
+}
+
+func Test_PoolScope_concurrent(t *testing.T) {
+	// arrange
+	scope := NewPoolScope(1, 3)
+	atomic.StoreInt32(&Test_pool_created, 0)
+	ctx, e := Context().Scope("pool", scope).Build(Beans(
+		Bean(Test_pool_struct{}).ID("1").Scope("pool").Factory(newTest_pool_struct).Property("Name", "a"),
+	)...)
+	require.Nil(t, e)
+	var borrowed, most int32
+	errs := make([]error, 20)
+
+	// action
+	var wg sync.WaitGroup
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			bean, e := ctx.GetBean("1")
+			if e != nil {
+				errs[i] = e
+				return
+			}
+			n := atomic.AddInt32(&borrowed, 1)
+			for {
+				m := atomic.LoadInt32(&most)
+				if n <= m || atomic.CompareAndSwapInt32(&most, m, n) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			atomic.AddInt32(&borrowed, -1)
+			errs[i] = scope.Release(bean)
+		}(i)
+	}
+	wg.Wait()
+
+	// assert
+	for _, e := range errs {
+		require.Nil(t, e)
+	}
+	assert.True(t, atomic.LoadInt32(&most) <= 3)
+	assert.True(t, atomic.LoadInt32(&Test_pool_created) <= 3)
+}
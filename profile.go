@@ -0,0 +1,67 @@
+package gospring
+
+import (
+	"fmt"
+	"strings"
+)
+
+// filterByProfiles removes beans which aren't active under the active
+// profiles. IDs of the removed beans are kept to report meaningful errors.
+func (ctx *applicationContext) filterByProfiles(beans []BeanI) ([]BeanI, error) {
+
+	actives := make([]BeanI, 0, len(beans))
+
+	for _, bean := range beans {
+
+		sbean, ok := bean.(*structBean)
+		if !ok {
+			actives = append(actives, bean)
+			continue
+		}
+
+		active, e := isProfileActive(sbean.GetProfiles(), ctx.profiles)
+		if e != nil {
+			return nil, fmt.Errorf("Profile of bean [%v] is invalid. Caused by: %w", bean, e)
+		}
+
+		if active {
+			actives = append(actives, bean)
+		} else if id := bean.GetID(); id != nil {
+			ctx.disabledById[*id] = fmt.Sprintf("profile %v", sbean.GetProfiles())
+		}
+	}
+
+	return actives, nil
+}
+
+// isProfileActive returns true if any one of the expressions matches the
+// active profiles. An expression is a profile name, e.g. "prod", or a
+// negated one, e.g. "!test". A bean without any expression is always
+// active.
+func isProfileActive(expressions []string, profiles map[string]bool) (bool, error) {
+
+	if len(expressions) == 0 {
+		return true, nil
+	}
+
+	active := false
+
+	for _, expression := range expressions {
+
+		name := strings.TrimSpace(expression)
+		negated := strings.HasPrefix(name, "!")
+		if negated {
+			name = strings.TrimSpace(strings.TrimPrefix(name, "!"))
+		}
+
+		if name == "" || strings.ContainsAny(name, "!&| ") {
+			return false, fmt.Errorf("Invalid profile expression [%v]", expression)
+		}
+
+		if profiles[name] != negated {
+			active = true
+		}
+	}
+
+	return active, nil
+}
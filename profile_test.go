@@ -0,0 +1,116 @@
+package gospring
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Profiles(t *testing.T) {
+	// arrange
+	type beanStruct struct {
+		Name string
+	}
+	beans := Beans(
+		Bean(beanStruct{}).ID("1").Profile("prod").Property("Name", "prod"),
+		Bean(beanStruct{}).ID("1").Profile("!prod").Property("Name", "dev"),
+	)
+
+	// action
+	prod, e1 := Context().Profiles("prod").Build(beans...)
+	dev, e2 := Context().Build(beans...)
+
+	// assert
+	require.Nil(t, e1)
+	require.Nil(t, e2)
+	bean, e := prod.GetBean("1")
+	require.Nil(t, e)
+	assert.Equal(t, "prod", bean.(*beanStruct).Name)
+	bean, e = dev.GetBean("1")
+	require.Nil(t, e)
+	assert.Equal(t, "dev", bean.(*beanStruct).Name)
+}
+
+func Test_Profiles_refToDisabledBean(t *testing.T) {
+	// arrange
+	type beanStruct struct {
+		B *beanStruct
+	}
+	beans := Beans(
+		Bean(beanStruct{}).ID("1").Property("B", Ref("2")),
+		Bean(beanStruct{}).ID("2").Profile("prod"),
+	)
+
+	// action
+	_, e := Context().Profiles("test").Build(beans...)
+
+	// assert
+	require.NotNil(t, e)
+	assert.Contains(t, e.Error(), "disabled by profile")
+}
+
+func Test_Profiles_getDisabledBean(t *testing.T) {
+	// arrange
+	type beanStruct struct{}
+	beans := Beans(
+		Bean(beanStruct{}).ID("1").Profile("prod"),
+	)
+	ctx, e := NewApplicationContext(beans...)
+	require.Nil(t, e)
+
+	// action
+	bean, e := ctx.GetBean("1")
+
+	// assert
+	assert.Nil(t, bean)
+	require.NotNil(t, e)
+	assert.Contains(t, e.Error(), "disabled by profile")
+}
+
+func Test_Profiles_innerBean(t *testing.T) {
+	// arrange
+	type beanStruct struct {
+		B *beanStruct
+	}
+	beans := Beans(
+		Bean(beanStruct{}).ID("1").Property("B",
+			Bean(beanStruct{}).Profile("prod"),
+		),
+	)
+
+	// action
+	_, e := Context().Profiles("prod").Build(beans...)
+
+	// assert
+	assert.NotNil(t, e)
+}
+
+func Test_isProfileActive(t *testing.T) {
+	// arrange
+	profiles := map[string]bool{"prod": true}
+	cases := []struct {
+		expressions []string
+		active      bool
+		fail        bool
+	}{
+		{[]string{}, true, false},
+		{[]string{"prod"}, true, false},
+		{[]string{"test"}, false, false},
+		{[]string{"!test"}, true, false},
+		{[]string{"!prod"}, false, false},
+		{[]string{"test", "prod"}, true, false},
+		{[]string{"!prod", "test"}, false, false},
+		{[]string{"!"}, false, true},
+		{[]string{"a & b"}, false, true},
+	}
+
+	for _, c := range cases {
+		// action
+		active, e := isProfileActive(c.expressions, profiles)
+
+		// assert
+		assert.Equal(t, c.active, active, "%v", c.expressions)
+		assert.Equal(t, c.fail, e != nil, "%v", c.expressions)
+	}
+}
@@ -0,0 +1,45 @@
+package gospring
+
+import "reflect"
+
+// propertyBean is a value from the environment of the context. It's
+// converted into the type of the field or the argument it is injected into.
+type propertyBean struct {
+	key string
+}
+
+func (bean *propertyBean) GetKey() string {
+	return bean.key
+}
+
+func (bean *propertyBean) GetID() *string {
+	return nil
+}
+
+func (bean *propertyBean) GetScope() Scope {
+	return Prototype
+}
+
+func (bean *propertyBean) GetFactory() (interface{}, []BeanI) {
+	return nil, nil
+}
+
+func (bean *propertyBean) GetFinalize() *string {
+	return nil
+}
+
+func (bean *propertyBean) GetInit() *string {
+	return nil
+}
+
+func (bean *propertyBean) GetProperty(name string) []BeanI {
+	return nil
+}
+
+func (bean *propertyBean) GetProperties() map[string][]BeanI {
+	return map[string][]BeanI{}
+}
+
+func (bean *propertyBean) GetType() reflect.Type {
+	return reflect.TypeOf("")
+}
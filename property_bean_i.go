@@ -0,0 +1,5 @@
+package gospring
+
+type PropertyBeanI interface {
+	GetKey() string
+}
@@ -0,0 +1,169 @@
+package gospring
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// propertyPath is where a property is injected. The name of a property is
+// a field, a setter method, or a dotted path like "Server.TLS.CertFile"
+// whose last part is a field or a setter and the others are fields of
+// nested structs. Nil pointers to structs along the path are allocated
+// while injecting.
+type propertyPath struct {
+	// fields are indexes of fields from the bean to the last struct, or to
+	// the injected field if setter is empty.
+	fields [][]int
+
+	// setter is the name of the setter method of the last struct.
+	setter string
+
+	// tvpe is the type of the field or the parameter of the setter.
+	tvpe reflect.Type
+}
+
+// newPropertyPath resolves the name of a property against the type of the
+// bean.
+func newPropertyPath(tvpe reflect.Type, name string) (*propertyPath, error) {
+
+	if tvpe.Kind() == reflect.Ptr {
+		tvpe = tvpe.Elem()
+	}
+
+	path := &propertyPath{}
+	parts := strings.Split(name, ".")
+
+	for i, part := range parts {
+
+		if tvpe.Kind() != reflect.Struct {
+			return nil, fmt.Errorf("type [%v] has no fields", tvpe)
+		}
+
+		last := i == len(parts)-1
+
+		field, ok := tvpe.FieldByName(part)
+		if ok && field.PkgPath == "" {
+			// a nil pointer can't be allocated if it's unexported
+			for j := 1; j < len(field.Index); j++ {
+				embedded := tvpe.FieldByIndex(field.Index[:j])
+				if embedded.PkgPath != "" && embedded.Type.Kind() == reflect.Ptr {
+					return nil, fmt.Errorf("field [%s] is promoted through the unexported embedded pointer [%s]",
+						part, embedded.Name)
+				}
+			}
+			path.fields = append(path.fields, field.Index)
+			if last {
+				path.tvpe = field.Type
+				return path, nil
+			}
+			tvpe = field.Type
+			if tvpe.Kind() == reflect.Ptr && tvpe.Elem().Kind() == reflect.Struct {
+				tvpe = tvpe.Elem()
+			}
+			continue
+		}
+
+		if last {
+			if setter, present, e := findSetter(tvpe, part); present {
+				if e != nil {
+					return nil, e
+				}
+				path.setter = setter.Name
+				path.tvpe = setter.Type.In(1)
+				return path, nil
+			}
+		}
+
+		if ok {
+			return nil, fmt.Errorf("field [%s] is unexported", part)
+		}
+		return nil, fmt.Errorf("type [%v] has no field [%s]", tvpe, part)
+	}
+
+	return path, nil
+}
+
+// findSetter finds the method of *T which is either the name itself or the
+// name prefixed with "Set", e.g. both "Timeout" and "SetTimeout" find the
+// method SetTimeout(...).
+func findSetter(tvpe reflect.Type, name string) (method reflect.Method, present bool, e error) {
+
+	ptrType := reflect.PtrTo(tvpe)
+
+	method, present = ptrType.MethodByName(name)
+	if !present {
+		r, size := utf8.DecodeRuneInString(name)
+		method, present = ptrType.MethodByName("Set" + string(unicode.ToUpper(r)) + name[size:])
+	}
+	if !present {
+		return method, false, nil
+	}
+
+	// the receiver is the first input
+	errorType := reflect.TypeOf((*error)(nil)).Elem()
+	switch {
+	case method.Type.NumIn() != 2:
+		e = fmt.Errorf("setter [%s] must have exactly one parameter", method.Name)
+	case method.Type.NumOut() > 1 || method.Type.NumOut() == 1 && method.Type.Out(0) != errorType:
+		e = fmt.Errorf("setter [%s] can only return an error", method.Name)
+	}
+
+	return method, true, e
+}
+
+// inject walks the path from the bean, which is a pointer to a struct, and
+// calls fn to inject the value into the field, or into the argument of the
+// setter which is called after.
+func (path *propertyPath) inject(bean reflect.Value, fn func(field reflect.Value) error) error {
+
+	holder := bean.Elem()
+	for _, index := range path.fields {
+		holder = fieldByIndex(holder, index)
+	}
+
+	if path.setter == "" {
+		return fn(holder)
+	}
+
+	argv := reflect.New(path.tvpe).Elem()
+	if e := fn(argv); e != nil {
+		return e
+	}
+
+	if holder.Kind() == reflect.Ptr {
+		holder = allocate(holder)
+	} else {
+		holder = holder.Addr()
+	}
+
+	returns := holder.MethodByName(path.setter).Call([]reflect.Value{argv})
+	if len(returns) > 0 && !returns[0].IsNil() {
+		return fmt.Errorf("Setter [%s] returns an error. Caused by: %w",
+			path.setter, returns[0].Interface().(error))
+	}
+
+	return nil
+}
+
+// fieldByIndex is the same as reflect.Value.FieldByIndex(...) but allocates
+// nil pointers to structs instead of panicking.
+func fieldByIndex(value reflect.Value, index []int) reflect.Value {
+	for _, i := range index {
+		if value.Kind() == reflect.Ptr {
+			value = allocate(value).Elem()
+		}
+		value = value.Field(i)
+	}
+	return value
+}
+
+// allocate sets a nil pointer to a new zero value, and returns the pointer.
+func allocate(ptr reflect.Value) reflect.Value {
+	if ptr.IsNil() {
+		ptr.Set(reflect.New(ptr.Type().Elem()))
+	}
+	return ptr
+}
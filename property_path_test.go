@@ -0,0 +1,168 @@
+package gospring
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Test_propertyPath_tls struct {
+	CertFile string
+	KeyFile  string
+}
+
+type Test_propertyPath_server struct {
+	Host string
+	TLS  *Test_propertyPath_tls
+}
+
+type Test_propertyPath_Embedded struct {
+	Region string
+}
+
+type Test_propertyPath_config struct {
+	*Test_propertyPath_Embedded
+	Server  Test_propertyPath_server
+	Backup  *Test_propertyPath_server
+	timeout int
+	names   []string
+}
+
+func (c *Test_propertyPath_config) SetTimeout(timeout int) {
+	c.timeout = timeout
+}
+
+func (c *Test_propertyPath_config) SetNames(names []string) error {
+	for _, name := range names {
+		if name == "" {
+			return errors.New("empty name")
+		}
+	}
+	c.names = names
+	return nil
+}
+
+func (c *Test_propertyPath_config) SetBroken(a, b int) {
+}
+
+func Test_Property_setter(t *testing.T) {
+	// arrange
+	ctx, e := NewApplicationContext(Beans(
+		Bean(Test_propertyPath_config{}).ID("1").
+			Property("Timeout", 10).
+			Property("SetNames", "a", "b"),
+	)...)
+	require.Nil(t, e)
+
+	// action
+	bean, e := ctx.GetBean("1")
+
+	// assert
+	require.Nil(t, e)
+	config := bean.(*Test_propertyPath_config)
+	assert.Equal(t, 10, config.timeout)
+	assert.Equal(t, []string{"a", "b"}, config.names)
+}
+
+func Test_Property_setterReturnsError(t *testing.T) {
+	// arrange
+	ctx, e := NewApplicationContext(Beans(
+		Bean(Test_propertyPath_config{}).ID("1").Property("Names", "a", ""),
+	)...)
+	require.Nil(t, e)
+
+	// action
+	bean, e := ctx.GetBean("1")
+
+	// assert
+	assert.Nil(t, bean)
+	var ce *BeanCreationError
+	require.True(t, errors.As(e, &ce))
+	assert.Equal(t, "Names", ce.Field)
+	assert.Contains(t, e.Error(), "Setter [SetNames] returns an error. Caused by: empty name")
+}
+
+func Test_Property_nestedPath(t *testing.T) {
+	// arrange
+	ctx, e := NewApplicationContext(Beans(
+		Bean(Test_propertyPath_config{}).ID("1").
+			Property("Server.Host", "localhost").
+			Property("Server.TLS.CertFile", "a.crt").
+			Property("Server.TLS.KeyFile", "a.key").
+			Property("Backup.TLS.CertFile", "b.crt").
+			Property("Region", "eu"),
+	)...)
+	require.Nil(t, e)
+
+	// action
+	bean, e := ctx.GetBean("1")
+
+	// assert
+	require.Nil(t, e)
+	config := bean.(*Test_propertyPath_config)
+	assert.Equal(t, "localhost", config.Server.Host)
+	assert.Equal(t, &Test_propertyPath_tls{CertFile: "a.crt", KeyFile: "a.key"}, config.Server.TLS)
+	require.NotNil(t, config.Backup)
+	assert.Equal(t, "", config.Backup.Host)
+	assert.Equal(t, "b.crt", config.Backup.TLS.CertFile)
+	require.NotNil(t, config.Test_propertyPath_Embedded)
+	assert.Equal(t, "eu", config.Region)
+}
+
+func Test_Property_nestedPathAfterParent(t *testing.T) {
+	// arrange
+	ctx, e := NewApplicationContext(Beans(
+		Bean(Test_propertyPath_config{}).ID("1").
+			Property("Backup.Host", "b").
+			Property("Backup", Bean(Test_propertyPath_server{}).Property("Host", "a")),
+	)...)
+	require.Nil(t, e)
+
+	// action
+	bean, e := ctx.GetBean("1")
+
+	// assert
+	require.Nil(t, e)
+	assert.Equal(t, "b", bean.(*Test_propertyPath_config).Backup.Host)
+}
+
+func Test_newPropertyPath_fail(t *testing.T) {
+	// arrange
+	tvpe := typeOfBean(Test_propertyPath_config{})
+
+	// action
+	_, e1 := newPropertyPath(tvpe, "Server.Unknown")
+	_, e2 := newPropertyPath(tvpe, "Server.Host.Length")
+	_, e3 := newPropertyPath(tvpe, "names.Length")
+	_, e4 := newPropertyPath(tvpe, "Broken")
+
+	// assert
+	assert.EqualError(t, e1, "type [gospring.Test_propertyPath_server] has no field [Unknown]")
+	assert.EqualError(t, e2, "type [string] has no fields")
+	assert.EqualError(t, e3, "field [names] is unexported")
+	assert.EqualError(t, e4, "setter [SetBroken] must have exactly one parameter")
+}
+
+func Test_validate_propertyPath(t *testing.T) {
+	// arrange
+	beans := Beans(
+		Bean(Test_propertyPath_config{}).ID("1").
+			Property("Server.TLS.CertFile", Bean(Test_propertyPath_tls{})).
+			Property("Server.Port", 80).
+			Property("Timeout", "aaa"),
+	)
+
+	// action
+	e := Context().Validate(beans...)
+
+	// assert
+	var ve *ValidationError
+	require.True(t, errors.As(e, &ve))
+	require.Len(t, ve.Problems, 3)
+	assert.Contains(t, ve.Problems[0].Error(), "1[gospring.Test_propertyPath_config].Server.Port: "+
+		"type [gospring.Test_propertyPath_server] has no field [Port]")
+	assert.Contains(t, ve.Problems[1].Error(), "1[gospring.Test_propertyPath_config].Server.TLS.CertFile -> anonymous[gospring.Test_propertyPath_tls]: ")
+	assert.Contains(t, ve.Problems[2].Error(), "1[gospring.Test_propertyPath_config].Timeout -> (value string): ")
+}
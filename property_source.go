@@ -0,0 +1,105 @@
+package gospring
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+type mapSource struct {
+	name       string
+	properties map[string]string
+}
+
+// MapSource creates a property source from a map. The map is copied.
+func MapSource(name string, properties map[string]string) PropertySourceI {
+	source := &mapSource{
+		name:       name,
+		properties: make(map[string]string),
+	}
+	for key, value := range properties {
+		source.properties[key] = value
+	}
+	return source
+}
+
+func (source *mapSource) GetName() string {
+	return source.name
+}
+
+func (source *mapSource) GetProperty(key string) (string, bool) {
+	value, present := source.properties[key]
+	return value, present
+}
+
+type envSource struct{}
+
+// EnvSource creates a property source of environment variables of the
+// process. A key like "server.port" matches the variable "server.port" or
+// "SERVER_PORT".
+func EnvSource() PropertySourceI {
+	return &envSource{}
+}
+
+func (source *envSource) GetName() string {
+	return "env"
+}
+
+func (source *envSource) GetProperty(key string) (string, bool) {
+	if value, present := os.LookupEnv(key); present {
+		return value, true
+	}
+	name := strings.ToUpper(strings.NewReplacer(".", "_", "-", "_").Replace(key))
+	return os.LookupEnv(name)
+}
+
+// ArgsSource creates a property source from command-line arguments like
+// "--server.port=8080". An argument without value, e.g. "--debug", is
+// "true". Other arguments are ignored.
+func ArgsSource(args []string) PropertySourceI {
+	properties := make(map[string]string)
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, "--") || arg == "--" {
+			continue
+		}
+		arg = strings.TrimPrefix(arg, "--")
+		if i := strings.Index(arg, "="); i >= 0 {
+			properties[arg[:i]] = arg[i+1:]
+		} else {
+			properties[arg] = "true"
+		}
+	}
+	return MapSource("args", properties)
+}
+
+// FileSource creates a property source from a file with lines like
+// "key=value" or "key: value". Empty lines and lines starting with "#" or
+// "!" are ignored.
+func FileSource(path string) (PropertySourceI, error) {
+
+	file, e := os.Open(path)
+	if e != nil {
+		return nil, fmt.Errorf("Can't open file [%v]. Caused by: %w", path, e)
+	}
+	defer file.Close()
+
+	properties := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!") {
+			continue
+		}
+		i := strings.IndexAny(line, "=:")
+		if i <= 0 {
+			return nil, fmt.Errorf("Invalid property at [%v:%d]: [%v]", path, n, line)
+		}
+		properties[strings.TrimSpace(line[:i])] = strings.TrimSpace(line[i+1:])
+	}
+	if e := scanner.Err(); e != nil {
+		return nil, fmt.Errorf("Can't read file [%v]. Caused by: %w", path, e)
+	}
+
+	return MapSource(path, properties), nil
+}
@@ -0,0 +1,6 @@
+package gospring
+
+type PropertySourceI interface {
+	GetName() string
+	GetProperty(key string) (string, bool)
+}
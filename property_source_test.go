@@ -0,0 +1,105 @@
+package gospring
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_MapSource(t *testing.T) {
+	// arrange
+	m := map[string]string{"a": "1"}
+	source := MapSource("m", m)
+
+	// action
+	m["a"] = "2"
+	value, present := source.GetProperty("a")
+	_, absent := source.GetProperty("b")
+
+	// assert
+	assert.Equal(t, "m", source.GetName())
+	assert.True(t, present)
+	assert.Equal(t, "1", value)
+	assert.False(t, absent)
+}
+
+func Test_EnvSource(t *testing.T) {
+	// arrange
+	os.Setenv("TEST_ENV_SOURCE_PORT", "8080")
+	defer os.Unsetenv("TEST_ENV_SOURCE_PORT")
+	source := EnvSource()
+
+	// action
+	value, present := source.GetProperty("test.env-source.port")
+
+	// assert
+	assert.True(t, present)
+	assert.Equal(t, "8080", value)
+}
+
+func Test_ArgsSource(t *testing.T) {
+	// arrange
+	source := ArgsSource([]string{"--a=1", "--b", "c", "--d=x=y", "--"})
+
+	// action
+	a, _ := source.GetProperty("a")
+	b, _ := source.GetProperty("b")
+	_, c := source.GetProperty("c")
+	d, _ := source.GetProperty("d")
+
+	// assert
+	assert.Equal(t, "1", a)
+	assert.Equal(t, "true", b)
+	assert.False(t, c)
+	assert.Equal(t, "x=y", d)
+}
+
+func Test_FileSource(t *testing.T) {
+	// arrange
+	file, e := ioutil.TempFile("", "gospring")
+	require.Nil(t, e)
+	defer os.Remove(file.Name())
+	file.WriteString("# comment\n\na = 1\nb: 2\n! comment\n")
+	file.Close()
+
+	// action
+	source, e := FileSource(file.Name())
+
+	// assert
+	require.Nil(t, e)
+	a, _ := source.GetProperty("a")
+	b, _ := source.GetProperty("b")
+	assert.Equal(t, "1", a)
+	assert.Equal(t, "2", b)
+}
+
+func Test_FileSource_invalidLine(t *testing.T) {
+	// arrange
+	file, e := ioutil.TempFile("", "gospring")
+	require.Nil(t, e)
+	defer os.Remove(file.Name())
+	file.WriteString("a = 1\nbbb\n")
+	file.Close()
+
+	// action
+	source, e := FileSource(file.Name())
+
+	// assert
+	assert.Nil(t, source)
+	require.NotNil(t, e)
+	assert.Contains(t, e.Error(), ":2]")
+}
+
+func Test_FileSource_notExist(t *testing.T) {
+	// arrange
+
+	// action
+	source, e := FileSource("/not/exist")
+
+	// assert
+	assert.Nil(t, source)
+	assert.NotNil(t, e)
+}
@@ -0,0 +1,215 @@
+package gospring
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync"
+)
+
+type requestScopeKey struct{}
+
+type applicationContextKey struct{}
+
+// WithRequestScope opens a request scope, and returns a copy of parent which
+// holds the scope and the application context, with a function to close the
+// scope. Closing the scope finalizes beans created in it, in the reverse
+// order they are created.
+//
+// c, closeScope := WithRequestScope(r.Context(), ctx)
+// defer closeScope()
+// bean, e := GetBean(c, "request_id")
+func WithRequestScope(parent context.Context, ctx ApplicationContextI) (context.Context, func() error) {
+	scope := newRequestScope()
+	c := context.WithValue(parent, requestScopeKey{}, scope)
+	c = context.WithValue(c, applicationContextKey{}, ctx)
+	return c, scope.close
+}
+
+// GetBean acquires a bean from the application context held by c, which is
+// returned by WithRequestScope(...) or given to handlers by
+// RequestScopeHandler(...).
+func GetBean(c context.Context, id string) (interface{}, error) {
+	ctx, ok := c.Value(applicationContextKey{}).(ApplicationContextI)
+	if !ok {
+		return nil, fmt.Errorf("There is no application context in the context")
+	}
+	return ctx.GetBeanContext(c, id)
+}
+
+// RequestScopeHandler is a middleware which opens a request scope for each
+// request, and closes it after next returns. Handlers get beans by
+// GetBean(r.Context(), id).
+func RequestScopeHandler(ctx ApplicationContextI, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, closeScope := WithRequestScope(r.Context(), ctx)
+		defer closeScope()
+		next.ServeHTTP(w, r.WithContext(c))
+	})
+}
+
+// getRequestBean gets the bean from the request scope held by the context
+// of the creation.
+func (ctx *applicationContext) getRequestBean(bean BeanI, path resolutionPath, c *creation) (*reflect.Value, error) {
+
+	var scope *requestScope
+	if c != nil && c.context != nil {
+		scope, _ = c.context.Value(requestScopeKey{}).(*requestScope)
+	}
+	if scope == nil {
+		return nil, newBeanCreationError(bean, "", path.with(resolutionStep(bean)), ErrNoRequestScope)
+	}
+
+	// the scope is shared by the context and its parents, whose beans may
+	// have the same ID
+	name := fmt.Sprintf("%p/%s", ctx, scopedName(bean))
+	return ctx.getScopedBean(bean, name, scope, path, c)
+}
+
+// requestScope holds beans of one request, which may be of different
+// contexts, e.g. a parent and a child context.
+type requestScope struct {
+	lock      sync.Mutex
+	objects   map[string]interface{}
+	callbacks map[string]func() error
+
+	// creating are locks held while objects are created, so an object is
+	// created once even if goroutines of the request ask for it together
+	creating map[string]*sync.Mutex
+
+	// names are in the order beans are created
+	names  []string
+	closed bool
+}
+
+func newRequestScope() *requestScope {
+	return &requestScope{
+		objects:   make(map[string]interface{}),
+		callbacks: make(map[string]func() error),
+		creating:  make(map[string]*sync.Mutex),
+	}
+}
+
+// Get doesn't hold the lock of the scope while creating the bean, which may
+// depend on other beans in the scope. Beans depending on each other have
+// different names, so there is no dead lock while creating them.
+func (scope *requestScope) Get(name string, create func() (interface{}, error)) (interface{}, error) {
+
+	scope.lock.Lock()
+	if scope.closed {
+		scope.lock.Unlock()
+		return nil, fmt.Errorf("The request scope is closed")
+	}
+	creating, present := scope.creating[name]
+	if !present {
+		creating = &sync.Mutex{}
+		scope.creating[name] = creating
+	}
+	scope.lock.Unlock()
+
+	creating.Lock()
+	defer creating.Unlock()
+
+	scope.lock.Lock()
+	object, present := scope.objects[name]
+	closed := scope.closed
+	scope.lock.Unlock()
+
+	if closed {
+		return nil, fmt.Errorf("The request scope is closed")
+	}
+	if present {
+		return object, nil
+	}
+
+	created, e := create()
+	if e != nil {
+		return nil, e
+	}
+
+	scope.lock.Lock()
+	defer scope.lock.Unlock()
+
+	// the object is finalized by RegisterDestructionCallback(...)
+	if scope.closed {
+		return nil, fmt.Errorf("The request scope is closed")
+	}
+
+	scope.objects[name] = created
+	scope.names = append(scope.names, name)
+	return created, nil
+}
+
+func (scope *requestScope) Remove(name string) interface{} {
+	scope.lock.Lock()
+	defer scope.lock.Unlock()
+
+	object, present := scope.objects[name]
+	if !present {
+		return nil
+	}
+
+	delete(scope.objects, name)
+	delete(scope.callbacks, name)
+	for i, n := range scope.names {
+		if n == name {
+			scope.names = append(scope.names[:i], scope.names[i+1:]...)
+			break
+		}
+	}
+	return object
+}
+
+// RegisterDestructionCallback ignores objects which aren't kept by the scope,
+// e.g. one removed already. The callback is called at once if the scope is
+// closed already, since the object is dropped by the scope.
+func (scope *requestScope) RegisterDestructionCallback(name string, object interface{}, callback func() error) error {
+	scope.lock.Lock()
+	closed := scope.closed
+	if !closed && isSameObject(scope.objects[name], object) {
+		scope.callbacks[name] = callback
+	}
+	scope.lock.Unlock()
+
+	if !closed {
+		return nil
+	}
+	if e := callback(); e != nil {
+		return fmt.Errorf("Can't finalize bean [%v] in the closed request scope. Caused by: %w", name, e)
+	}
+	return nil
+}
+
+// close calls destruction callbacks in the reverse order beans are created.
+// All of them are called even if some fail, and the first error is
+// returned. Callbacks are called without the lock, since finalizers may get
+// other beans.
+func (scope *requestScope) close() error {
+	scope.lock.Lock()
+	if scope.closed {
+		scope.lock.Unlock()
+		return nil
+	}
+	scope.closed = true
+	names := scope.names
+	callbacks := scope.callbacks
+	scope.objects = nil
+	scope.callbacks = nil
+	scope.creating = nil
+	scope.names = nil
+	scope.lock.Unlock()
+
+	var first error
+	for i := len(names) - 1; i >= 0; i-- {
+		name := names[i]
+		callback, present := callbacks[name]
+		if !present {
+			continue
+		}
+		if e := callback(); e != nil && first == nil {
+			first = fmt.Errorf("Can't finalize bean [%v] in the request scope. Caused by: %w", name, e)
+		}
+	}
+	return first
+}
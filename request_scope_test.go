@@ -0,0 +1,296 @@
+package gospring
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Test_request_struct struct {
+	Name      string
+	Other     *Test_request_struct
+	finalized bool
+}
+
+var Test_request_finalized []string
+
+func (s *Test_request_struct) Finalize() {
+	s.finalized = true
+	Test_request_finalized = append(Test_request_finalized, s.Name)
+}
+
+func (s *Test_request_struct) Fail() error {
+	return errors.New("failed")
+}
+
+var Test_request_context context.Context
+
+type Test_request_getter struct {
+	e error
+}
+
+func (s *Test_request_getter) Finalize() {
+	_, s.e = GetBean(Test_request_context, "b")
+}
+
+type Test_request_holder struct {
+	Request *Test_request_struct
+}
+
+func Test_WithRequestScope(t *testing.T) {
+	// arrange
+	Test_request_finalized = []string{}
+	ctx, e := NewApplicationContext(Beans(
+		Bean(Test_request_struct{}).ID("a").Scope(Request).Property("Name", "a").Property("Other", Ref("b")),
+		Bean(Test_request_struct{}).ID("b").Scope(Request).Property("Name", "b"),
+	)...)
+	require.Nil(t, e)
+	c1, close1 := WithRequestScope(context.Background(), ctx)
+	c2, close2 := WithRequestScope(context.Background(), ctx)
+
+	// action
+	a1, e1 := GetBean(c1, "a")
+	b1, e2 := ctx.GetBeanContext(c1, "b")
+	a2, e3 := GetBean(c2, "a")
+	ec1 := close1()
+	ec2 := close2()
+
+	// assert
+	require.Nil(t, e1)
+	require.Nil(t, e2)
+	require.Nil(t, e3)
+	require.Nil(t, ec1)
+	require.Nil(t, ec2)
+	assert.True(t, a1.(*Test_request_struct).Other == b1)
+	assert.False(t, a1 == a2)
+	assert.True(t, a1.(*Test_request_struct).finalized)
+	assert.True(t, b1.(*Test_request_struct).finalized)
+	assert.Equal(t, []string{"a", "b", "a", "b"}, Test_request_finalized)
+}
+
+func Test_WithRequestScope_closed(t *testing.T) {
+	// arrange
+	ctx, e := NewApplicationContext(Beans(
+		Bean(Test_request_struct{}).ID("a").Scope(Request),
+	)...)
+	require.Nil(t, e)
+	c, closeScope := WithRequestScope(context.Background(), ctx)
+	require.Nil(t, closeScope())
+
+	// action
+	bean, e := GetBean(c, "a")
+
+	// assert
+	assert.Nil(t, bean)
+	assert.NotNil(t, e)
+}
+
+func Test_WithRequestScope_finalizeFailed(t *testing.T) {
+	// arrange
+	ctx, e := NewApplicationContext(Beans(
+		Bean(Test_request_struct{}).ID("a").Scope(Request).Finalize("Fail"),
+	)...)
+	require.Nil(t, e)
+	c, closeScope := WithRequestScope(context.Background(), ctx)
+	_, e = GetBean(c, "a")
+	require.Nil(t, e)
+
+	// action
+	e = closeScope()
+
+	// assert
+	assert.NotNil(t, e)
+}
+
+func Test_WithRequestScope_finalizerGetsBean(t *testing.T) {
+	// arrange
+	ctx, e := NewApplicationContext(Beans(
+		Bean(Test_request_getter{}).ID("a").Scope(Request),
+		Bean(Test_request_struct{}).ID("b").Scope(Request),
+	)...)
+	require.Nil(t, e)
+	c, closeScope := WithRequestScope(context.Background(), ctx)
+	Test_request_context = c
+	a, e := GetBean(c, "a")
+	require.Nil(t, e)
+
+	// action
+	done := make(chan error)
+	go func() {
+		done <- closeScope()
+	}()
+
+	// assert
+	select {
+	case ec := <-done:
+		assert.Nil(t, ec)
+		assert.NotNil(t, a.(*Test_request_getter).e, "the scope is closed")
+	case <-time.After(time.Second):
+		assert.Fail(t, "a finalizer getting a bean is blocked")
+	}
+}
+
+func Test_WithRequestScope_concurrent(t *testing.T) {
+	// arrange
+	Test_request_finalized = []string{}
+	var created int32
+	ctx, e := NewApplicationContext(Beans(
+		Bean(Test_request_struct{}).ID("a").Scope(Request).Factory(func() *Test_request_struct {
+			atomic.AddInt32(&created, 1)
+			time.Sleep(time.Millisecond)
+			return &Test_request_struct{Name: "a"}
+		}),
+	)...)
+	require.Nil(t, e)
+	c, closeScope := WithRequestScope(context.Background(), ctx)
+	beans := make([]interface{}, 10)
+	errs := make([]error, 10)
+
+	// action
+	var wg sync.WaitGroup
+	for i := range beans {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			beans[i], errs[i] = GetBean(c, "a")
+		}(i)
+	}
+	wg.Wait()
+	ec := closeScope()
+
+	// assert
+	require.Nil(t, ec)
+	for i := range beans {
+		require.Nil(t, errs[i])
+		assert.True(t, beans[i] == beans[0])
+	}
+	assert.Equal(t, int32(1), created)
+	assert.Equal(t, []string{"a"}, Test_request_finalized)
+}
+
+func Test_requestScope_registerAfterClosed(t *testing.T) {
+	// arrange
+	scope := newRequestScope()
+	require.Nil(t, scope.close())
+	called := false
+
+	// action
+	e := scope.RegisterDestructionCallback("a", 1, func() error {
+		called = true
+		return errors.New("failed")
+	})
+
+	// assert
+	assert.True(t, called)
+	require.NotNil(t, e)
+	assert.Contains(t, e.Error(), "Caused by: failed")
+}
+
+func Test_GetBean_noRequestScope(t *testing.T) {
+	// arrange
+	ctx, e := (&contextBuilder{skipValidation: true}).Build(Beans(
+		Bean(Test_request_struct{}).ID("a").Scope(Request),
+		Bean(Test_request_holder{}).ID("b").Property("Request", Ref("a")),
+	)...)
+	require.Nil(t, e)
+	c, closeScope := WithRequestScope(context.Background(), ctx)
+	defer closeScope()
+
+	// action
+	_, e1 := ctx.GetBean("a")
+	_, e2 := GetBean(c, "b")
+	_, e3 := GetBean(context.Background(), "a")
+
+	// assert
+	assert.True(t, errors.Is(e1, ErrNoRequestScope))
+	assert.True(t, errors.Is(e2, ErrNoRequestScope), "a singleton doesn't belong to a request")
+	assert.NotNil(t, e3)
+}
+
+func Test_GetBeanContext_parent(t *testing.T) {
+	// arrange
+	parent, e := NewApplicationContext(Beans(
+		Bean(Test_request_struct{}).ID("a").Scope(Request),
+	)...)
+	require.Nil(t, e)
+	ctx, e := Context().Parent(parent).Build(Beans(
+		Bean(Test_request_holder{}).ID("b").Prototype().Property("Request", Ref("a")),
+	)...)
+	require.Nil(t, e)
+	c, closeScope := WithRequestScope(context.Background(), ctx)
+	defer closeScope()
+
+	// action
+	a, e1 := GetBean(c, "a")
+	b, e2 := GetBean(c, "b")
+
+	// assert
+	require.Nil(t, e1)
+	require.Nil(t, e2)
+	assert.True(t, b.(*Test_request_holder).Request == a)
+}
+
+func Test_GetBeanContext_sameIDInParent(t *testing.T) {
+	// arrange
+	parent, e := NewApplicationContext(Beans(
+		Bean(Test_request_struct{}).ID("a").Scope(Request).Property("Name", "parent"),
+	)...)
+	require.Nil(t, e)
+	ctx, e := Context().Parent(parent).Build(Beans(
+		Bean(Test_request_struct{}).ID("a").Scope(Request).Property("Name", "child"),
+	)...)
+	require.Nil(t, e)
+	c, closeScope := WithRequestScope(context.Background(), ctx)
+	defer closeScope()
+
+	// action
+	a1, e1 := parent.GetBeanContext(c, "a")
+	a2, e2 := ctx.GetBeanContext(c, "a")
+
+	// assert
+	require.Nil(t, e1)
+	require.Nil(t, e2)
+	assert.Equal(t, "parent", a1.(*Test_request_struct).Name)
+	assert.Equal(t, "child", a2.(*Test_request_struct).Name)
+}
+
+func Test_RequestScopeHandler(t *testing.T) {
+	// arrange
+	Test_request_finalized = []string{}
+	ctx, e := NewApplicationContext(Beans(
+		Bean(Test_request_struct{}).ID("a").Scope(Request).Property("Name", "a"),
+		Bean(Test_request_holder{}).ID("b").Prototype().Property("Request", Ref("a")),
+	)...)
+	require.Nil(t, e)
+	handler := RequestScopeHandler(ctx, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		a, e1 := GetBean(r.Context(), "a")
+		b, e2 := GetBean(r.Context(), "b")
+		if e1 != nil || e2 != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprintf(w, "%p %v", a, b.(*Test_request_holder).Request == a)
+	}))
+
+	// action
+	w1 := httptest.NewRecorder()
+	handler.ServeHTTP(w1, httptest.NewRequest("GET", "/", nil))
+	w2 := httptest.NewRecorder()
+	handler.ServeHTTP(w2, httptest.NewRequest("GET", "/", nil))
+
+	// assert
+	assert.Equal(t, http.StatusOK, w1.Code)
+	assert.Equal(t, http.StatusOK, w2.Code)
+	assert.Contains(t, w1.Body.String(), " true")
+	assert.Contains(t, w2.Body.String(), " true")
+	assert.Equal(t, []string{"a", "a"}, Test_request_finalized)
+}
@@ -0,0 +1,78 @@
+package gospring
+
+import (
+	"fmt"
+	"strings"
+)
+
+// resolutionPath is the stack of beans and fields being resolved when a
+// bean is created, e.g.
+//
+// ["a_id[main.Astruct].Asingleton", "b_id[main.Bstruct].Name", "(value int)"]
+type resolutionPath []string
+
+// with returns a new path with steps appended.
+func (path resolutionPath) with(steps ...string) resolutionPath {
+	return append(path[:len(path):len(path)], steps...)
+}
+
+// index returns a new path whose last step is an element of a slice or a
+// map, e.g. "a_id[main.Astruct].Names[0]".
+func (path resolutionPath) index(key interface{}) resolutionPath {
+	indexed := append(resolutionPath{}, path...)
+	indexed[len(indexed)-1] += fmt.Sprintf("[%v]", key)
+	return indexed
+}
+
+func (path resolutionPath) String() string {
+	return strings.Join(path, " -> ")
+}
+
+// resolutionStep describes the bean with its Go type in a resolution path.
+func resolutionStep(bean BeanI) string {
+	switch b := bean.(type) {
+	case ReferenceBeanI:
+		if target := b.GetReference(); target != nil {
+			return resolutionStep(target)
+		}
+		return fmt.Sprintf("%s[unresolved]", *bean.GetID())
+	case *parentBean:
+		return fmt.Sprintf("%s[%v] in parent", b.id, b.GetType())
+	case *propertyBean:
+		return fmt.Sprintf("(property %s)", b.GetKey())
+	case *valueBean:
+		return fmt.Sprintf("(value %v)", b.GetType())
+	}
+	name := "anonymous"
+	if id := bean.GetID(); id != nil {
+		name = *id
+	}
+	return fmt.Sprintf("%s[%v]", name, bean.GetType())
+}
+
+// resolutionError is an error where a bean is being injected. It becomes
+// a BeanCreationError of the bean the field belongs to.
+type resolutionError struct {
+	path  resolutionPath
+	cause error
+}
+
+func (e *resolutionError) Error() string {
+	return fmt.Sprintf("%v at [%v]", e.cause, e.path)
+}
+
+func (e *resolutionError) Unwrap() error {
+	return e.cause
+}
+
+func newBeanCreationError(bean BeanI, field string, path resolutionPath, e error) *BeanCreationError {
+	if re, ok := e.(*resolutionError); ok {
+		path, e = re.path, re.cause
+	}
+	return &BeanCreationError{
+		BeanID: beanName(bean),
+		Field:  field,
+		Path:   path,
+		Cause:  e,
+	}
+}
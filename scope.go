@@ -0,0 +1,94 @@
+package gospring
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// getScopedBean gets the object with the name from the scope of the bean,
+// which creates it by the context if it doesn't exist.
+func (ctx *applicationContext) getScopedBean(bean BeanI, name string, scope ScopeI, path resolutionPath, c *creation) (*reflect.Value, error) {
+
+	// create may be called more than once, e.g. to fill a pool
+	var lock sync.Mutex
+	var created []reflect.Value
+	object, e := scope.Get(name, func() (interface{}, error) {
+		value, e := ctx.getPrototypeBean(bean, path, c)
+		if e != nil {
+			return nil, e
+		}
+		lock.Lock()
+		created = append(created, *value)
+		lock.Unlock()
+		return value.Interface(), nil
+	})
+
+	// callbacks are registered out of Get(...), which may hold a lock of
+	// the scope, even if Get(...) fails since some objects may be kept
+	lock.Lock()
+	for _, value := range created {
+		value := value
+		if ef := scope.RegisterDestructionCallback(name, value.Interface(), func() error {
+			return ctx.callFinalizeFunc(value, bean)
+		}); ef != nil {
+			e = errors.Join(e, ef)
+		}
+	}
+	if len(created) > 0 {
+		ctx.addScopedObject(bean, name, scope)
+	}
+	lock.Unlock()
+
+	if e != nil {
+		var ce *BeanCreationError
+		if errors.As(e, &ce) {
+			return nil, e
+		}
+		return nil, newBeanCreationError(bean, "", path.with(resolutionStep(bean)),
+			fmt.Errorf("Can't get the bean from scope [%v]. Caused by: %w", bean.GetScope(), e))
+	}
+
+	if object == nil {
+		return nil, newBeanCreationError(bean, "", path.with(resolutionStep(bean)),
+			fmt.Errorf("Scope [%v] returns nil", bean.GetScope()))
+	}
+
+	value := reflect.ValueOf(object)
+	return &value, nil
+}
+
+// isSameObject compares objects if they are comparable, or maps and slices
+// created by factories by their pointers. Other objects which can't be
+// compared are never the same.
+func isSameObject(a, b interface{}) bool {
+	if reflect.TypeOf(a) != reflect.TypeOf(b) {
+		return false
+	}
+	if a == nil {
+		return true
+	}
+
+	va := reflect.ValueOf(a)
+	vb := reflect.ValueOf(b)
+	switch {
+	case va.Comparable() && vb.Comparable():
+		return a == b
+	case va.Kind() == reflect.Map:
+		return va.Pointer() == vb.Pointer()
+	case va.Kind() == reflect.Slice:
+		return va.Pointer() == vb.Pointer() && va.Len() == vb.Len()
+	default:
+		return false
+	}
+}
+
+// scopedName is the name of the bean in its scope. It's the ID, or a name
+// unique for the anonymous bean.
+func scopedName(bean BeanI) string {
+	if id := bean.GetID(); id != nil {
+		return *id
+	}
+	return fmt.Sprintf("%s#%p", beanName(bean), bean)
+}
@@ -0,0 +1,31 @@
+package gospring
+
+// ScopeI is a lifecycle of beans other than Singleton and Prototype. It's
+// registered on a context by ContextBuilderI.Scope(...), and beans are put
+// into it by StructBeanI.Scope(...).
+//
+// All functions must be safe for concurrent use by multiple goroutines.
+type ScopeI interface {
+
+	// Get returns the object of the bean with the name in the scope. If it
+	// doesn't exist, create is called to create it. create may get other
+	// beans of the scope, so it mustn't be called with a lock which Get(...)
+	// acquires.
+	Get(name string, create func() (interface{}, error)) (interface{}, error)
+
+	// Remove removes the object from the scope, and returns it or nil if it
+	// doesn't exist. The destruction callback of the object is removed
+	// without being called.
+	Remove(name string) interface{}
+
+	// RegisterDestructionCallback registers the callback to be called when
+	// the object is destroyed by the scope, e.g. the end of a request. It's
+	// registered by the context for every object created by Get(...), after
+	// Get(...) returns. The object tells the scope which one the callback
+	// belongs to, since the created one may not be kept, e.g. when another
+	// goroutine creates it at the same time, and a scope may hold many
+	// objects of a bean, e.g. a pool. If the object is dropped already, e.g.
+	// the scope is closed, the callback is called at once and its error is
+	// returned.
+	RegisterDestructionCallback(name string, object interface{}, callback func() error) error
+}
@@ -0,0 +1,285 @@
+package gospring
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Test_scope struct {
+	lock      sync.Mutex
+	objects   map[string]interface{}
+	callbacks map[string]func() error
+	err       error
+}
+
+func newTest_scope() *Test_scope {
+	return &Test_scope{
+		objects:   make(map[string]interface{}),
+		callbacks: make(map[string]func() error),
+	}
+}
+
+func (s *Test_scope) Get(name string, create func() (interface{}, error)) (interface{}, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.err != nil {
+		return nil, s.err
+	}
+	if object, present := s.objects[name]; present {
+		return object, nil
+	}
+	object, e := create()
+	if e != nil {
+		return nil, e
+	}
+	s.objects[name] = object
+	return object, nil
+}
+
+func (s *Test_scope) Remove(name string) interface{} {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	object := s.objects[name]
+	delete(s.objects, name)
+	delete(s.callbacks, name)
+	return object
+}
+
+func (s *Test_scope) RegisterDestructionCallback(name string, object interface{}, callback func() error) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.callbacks[name] = callback
+	return nil
+}
+
+func (s *Test_scope) destroy() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for name, callback := range s.callbacks {
+		if e := callback(); e != nil {
+			return e
+		}
+		delete(s.callbacks, name)
+	}
+	s.objects = make(map[string]interface{})
+	return nil
+}
+
+type Test_scope_struct struct {
+	Name      string
+	finalized bool
+}
+
+func (s *Test_scope_struct) Stop() {
+	s.finalized = true
+}
+
+type Test_scope_holder struct {
+	Scoped *Test_scope_struct
+}
+
+func Test_Scope(t *testing.T) {
+	// arrange
+	scope := newTest_scope()
+	ctx, e := Context().Scope("custom", scope).Build(Beans(
+		Bean(Test_scope_struct{}).ID("1").Scope("custom").Finalize("Stop").Property("Name", "a"),
+	)...)
+	require.Nil(t, e)
+
+	// action
+	bean1, e1 := ctx.GetBean("1")
+	bean2, e2 := ctx.GetBean("1")
+	ed := scope.destroy()
+	bean3, e3 := ctx.GetBean("1")
+
+	// assert
+	require.Nil(t, e1)
+	require.Nil(t, e2)
+	require.Nil(t, ed)
+	require.Nil(t, e3)
+	assert.True(t, bean1 == bean2)
+	assert.True(t, bean1.(*Test_scope_struct).finalized)
+	assert.False(t, bean1 == bean3)
+	assert.False(t, bean3.(*Test_scope_struct).finalized)
+	assert.Equal(t, "a", bean3.(*Test_scope_struct).Name)
+}
+
+func Test_Scope_remove(t *testing.T) {
+	// arrange
+	scope := newTest_scope()
+	ctx, e := Context().Scope("custom", scope).Build(Beans(
+		Bean(Test_scope_struct{}).ID("1").Scope("custom").Finalize("Stop"),
+	)...)
+	require.Nil(t, e)
+	bean, e := ctx.GetBean("1")
+	require.Nil(t, e)
+
+	// action
+	removed := scope.Remove("1")
+	ed := scope.destroy()
+
+	// assert
+	require.Nil(t, ed)
+	assert.True(t, removed == bean)
+	assert.False(t, bean.(*Test_scope_struct).finalized)
+}
+
+func Test_Scope_anonymous(t *testing.T) {
+	// arrange
+	scope := newTest_scope()
+	ctx, e := Context().Scope("custom", scope).Build(Beans(
+		Bean(Test_scope_holder{}).ID("1").Prototype().
+			Property("Scoped", Bean(Test_scope_struct{}).Scope("custom")),
+		Bean(Test_scope_holder{}).ID("2").Prototype().
+			Property("Scoped", Bean(Test_scope_struct{}).Scope("custom")),
+	)...)
+	require.Nil(t, e)
+
+	// action
+	bean1, e1 := ctx.GetBean("1")
+	bean2, e2 := ctx.GetBean("1")
+	bean3, e3 := ctx.GetBean("2")
+
+	// assert
+	require.Nil(t, e1)
+	require.Nil(t, e2)
+	require.Nil(t, e3)
+	assert.True(t, bean1.(*Test_scope_holder).Scoped == bean2.(*Test_scope_holder).Scoped)
+	assert.False(t, bean1.(*Test_scope_holder).Scoped == bean3.(*Test_scope_holder).Scoped)
+	assert.Len(t, scope.objects, 2)
+}
+
+func Test_Scope_getFailed(t *testing.T) {
+	// arrange
+	scope := newTest_scope()
+	scope.err = errors.New("failed")
+	ctx, e := Context().Scope("custom", scope).Build(Beans(
+		Bean(Test_scope_struct{}).ID("1").Scope("custom"),
+	)...)
+	require.Nil(t, e)
+
+	// action
+	bean, e := ctx.GetBean("1")
+
+	// assert
+	assert.Nil(t, bean)
+	assert.True(t, errors.Is(e, scope.err))
+	var ce *BeanCreationError
+	require.True(t, errors.As(e, &ce))
+	assert.Equal(t, "1", ce.BeanID)
+}
+
+func Test_Scope_buildFailed(t *testing.T) {
+	// arrange
+	scope := newTest_scope()
+	pool := NewPoolScope(0, 1)
+	var created *Test_scope_struct
+	beans := Beans(
+		Bean(Test_scope_holder{}).ID("1").Property("Scoped", Ref("s")),
+		Bean(Test_scope_struct{}).ID("2").Factory(func() (*Test_scope_struct, error) {
+			return nil, errors.New("failed")
+		}),
+		Bean(Test_scope_struct{}).ID("s").Scope("custom").Finalize("Stop").Factory(func() *Test_scope_struct {
+			created = &Test_scope_struct{}
+			return created
+		}),
+		Bean(Test_scope_struct{}).ID("p").Scope("pool"),
+	)
+
+	// action
+	ctx, e := Context().Scope("custom", scope).Scope("pool", pool).Eager().Build(beans...)
+
+	// assert
+	assert.Nil(t, ctx)
+	require.NotNil(t, e)
+	require.NotNil(t, created)
+	assert.True(t, created.finalized)
+	assert.Empty(t, scope.objects)
+	other, e := Context().Scope("pool", pool).Build(beans[3])
+	require.Nil(t, e)
+	_, e = other.GetBean("p")
+	assert.Nil(t, e, "the pool scope is still open")
+}
+
+func Test_Scope_unknown(t *testing.T) {
+	// arrange
+	beans := Beans(
+		Bean(Test_scope_struct{}).ID("1").Scope("custom"),
+	)
+
+	// action
+	ctx, e := NewApplicationContext(beans...)
+
+	// assert
+	assert.Nil(t, ctx)
+	assert.NotNil(t, e)
+}
+
+func Test_Scope_builtIn(t *testing.T) {
+	// arrange
+	builder := Context().Scope(Singleton, newTest_scope())
+
+	// action
+	ctx, e := builder.Build()
+
+	// assert
+	assert.Nil(t, ctx)
+	assert.NotNil(t, e)
+}
+
+func Test_Scope_loadJSON(t *testing.T) {
+	// arrange
+	registry := NewTypeRegistry().Type("struct", Test_scope_struct{})
+	beans, e := LoadJSON([]byte(`{"beans": [{"id": "1", "type": "struct", "scope": "custom"}]}`), registry)
+	require.Nil(t, e)
+
+	// action
+	ctx, e := Context().Scope("custom", newTest_scope()).Build(beans...)
+
+	// assert
+	require.Nil(t, e)
+	definition, e := ctx.GetBeanDefinition("1")
+	require.Nil(t, e)
+	assert.Equal(t, Scope("custom"), definition.GetScope())
+}
+
+func Test_isSameObject(t *testing.T) {
+	// arrange
+	m := map[string]string{}
+	sl := []int{1, 2}
+	f := func() {}
+	i := 1
+	cases := []struct {
+		a, b     interface{}
+		expected bool
+	}{
+		{nil, nil, true},
+		{&i, &i, true},
+		{&i, new(int), false},
+		{1, 1, true},
+		{1, int64(1), false},
+		{m, m, true},
+		{m, map[string]string{}, false},
+		{sl, sl, true},
+		{sl, sl[:1], false},
+		{sl, []int{1, 2}, false},
+		{f, f, false},
+		{[]interface{}{m}, []interface{}{m}, false},
+	}
+
+	for _, c := range cases {
+		// action
+		same := isSameObject(c.a, c.b)
+
+		// assert
+		assert.Equal(t, c.expected, same, "%#v %#v", c.a, c.b)
+	}
+}
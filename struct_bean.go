@@ -12,16 +12,23 @@ type structBean struct {
 	tvpe        reflect.Type
 	value       reflect.Value
 	properties  map[string][]BeanI
+	entries     map[string][]EntryI
 	factoryFn   interface{}
 	factoryArgv []BeanI
+	hasFactory  bool
+	buffer      *int
 	init        *string
 	finalize    *string
 	scope       Scope
+	lazy        *bool
+	profiles    []string
+	conditions  []condition
 }
 
 func (bean *structBean) Factory(fn interface{}, argv ...interface{}) StructBeanI {
 	bean.factoryFn = fn
 	bean.factoryArgv = Beans(argv...)
+	bean.hasFactory = true
 	return bean
 }
 
@@ -30,6 +37,12 @@ func (bean *structBean) Finalize(fnName string) StructBeanI {
 	return bean
 }
 
+func (bean *structBean) Eager() StructBeanI {
+	lazy := false
+	bean.lazy = &lazy
+	return bean
+}
+
 func (bean *structBean) ID(id string) StructBeanI {
 	bean.id = &id
 	return bean
@@ -40,8 +53,87 @@ func (bean *structBean) Init(fnName string) StructBeanI {
 	return bean
 }
 
+func (bean *structBean) Lazy() StructBeanI {
+	lazy := true
+	bean.lazy = &lazy
+	return bean
+}
+
+// Profile makes the bean be active only if one of the expressions matches
+// active profiles of the context. An expression is either a profile name,
+// e.g. "prod", or a negated one, e.g. "!test".
+func (bean *structBean) Profile(expressions ...string) StructBeanI {
+	bean.profiles = expressions
+	return bean
+}
+
+// OnBean makes the bean be added only if there is a bean with the ID.
+func (bean *structBean) OnBean(id string) StructBeanI {
+	bean.conditions = append(bean.conditions, onBean(id, true))
+	return bean
+}
+
+// OnBeanType makes the bean be added only if there is a bean which can be
+// assigned to the type of i. Use (*SomeInterface)(nil) for an interface.
+func (bean *structBean) OnBeanType(i interface{}) StructBeanI {
+	bean.conditions = append(bean.conditions, onBeanType(i, true))
+	return bean
+}
+
+// OnMissingBean makes the bean be added only if there isn't any bean with
+// the ID.
+func (bean *structBean) OnMissingBean(id string) StructBeanI {
+	bean.conditions = append(bean.conditions, onBean(id, false))
+	return bean
+}
+
+// OnMissingBeanType makes the bean be added only if there isn't any bean
+// which can be assigned to the type of i.
+func (bean *structBean) OnMissingBeanType(i interface{}) StructBeanI {
+	bean.conditions = append(bean.conditions, onBeanType(i, false))
+	return bean
+}
+
+// OnProperty makes the bean be added only if the property is set. If values
+// are given, the property must be one of them.
+func (bean *structBean) OnProperty(key string, values ...string) StructBeanI {
+	bean.conditions = append(bean.conditions, onProperty(key, values...))
+	return bean
+}
+
+// Property injects values into a field, a setter method, or a dotted path of
+// nested structs, e.g.
+//
+// Property("Timeout", 10)              // the field Timeout or SetTimeout(...)
+// Property("SetTimeout", 10)
+// Property("Server.TLS.CertFile", "a.crt")
+//
+// Nil pointers to structs along a path are allocated. Placeholders like
+// "${key}" in strings are resolved by the context, and "\${" is a literal
+// "${".
 func (bean *structBean) Property(name string, values ...interface{}) StructBeanI {
-	bean.properties[name] = Beans(values...)
+
+	// entries[i] is nil if values[i] isn't created by Entry(...)
+	entries := make([]EntryI, len(values))
+	hasEntry := false
+	unwrapped := make([]interface{}, len(values))
+
+	for i, value := range values {
+		if e, ok := value.(EntryI); ok {
+			entries[i] = e
+			unwrapped[i] = e.GetEntryValue()
+			hasEntry = true
+		} else {
+			unwrapped[i] = value
+		}
+	}
+
+	bean.properties[name] = Beans(unwrapped...)
+	if hasEntry {
+		bean.entries[name] = entries
+	} else {
+		delete(bean.entries, name)
+	}
 	return bean
 }
 
@@ -50,10 +142,31 @@ func (bean *structBean) TypeOf(i interface{}) StructBeanI {
 	return bean
 }
 
+// When makes the bean be added only if fn returns true.
+func (bean *structBean) When(fn func() bool) StructBeanI {
+	bean.conditions = append(bean.conditions, when(fn))
+	return bean
+}
+
+func (bean *structBean) GetConditions() []condition {
+	return bean.conditions
+}
+
 func (bean *structBean) GetFactory() (interface{}, []BeanI) {
 	return bean.factoryFn, bean.factoryArgv
 }
 
+// GetBuffer returns the buffer size if the bean is created by Chan(...).
+func (bean *structBean) GetBuffer() *int {
+	return bean.buffer
+}
+
+// HasFactory checks whether the factory is set by Factory(...) instead of
+// the default one.
+func (bean *structBean) HasFactory() bool {
+	return bean.hasFactory
+}
+
 func (bean *structBean) GetFinalize() *string {
 	return bean.finalize
 }
@@ -66,11 +179,25 @@ func (bean *structBean) GetInit() *string {
 	return bean.init
 }
 
+// GetLazy returns nil if the bean follows the setting of the context.
+func (bean *structBean) GetLazy() *bool {
+	return bean.lazy
+}
+
+func (bean *structBean) GetProfiles() []string {
+	return bean.profiles
+}
+
 func (bean *structBean) GetProperty(name string) []BeanI {
 	value, _ := bean.properties[name]
 	return value
 }
 
+func (bean *structBean) GetEntries(name string) []EntryI {
+	value, _ := bean.entries[name]
+	return value
+}
+
 func (bean *structBean) GetProperties() map[string][]BeanI {
 	return bean.properties
 }
@@ -88,6 +215,13 @@ func (bean *structBean) Singleton() StructBeanI {
 	return bean
 }
 
+// Scope puts the bean into the scope, which is either built-in or registered
+// by ContextBuilderI.Scope(...).
+func (bean *structBean) Scope(scope Scope) StructBeanI {
+	bean.scope = scope
+	return bean
+}
+
 func (bean *structBean) GetScope() Scope {
 	return bean.scope
 }
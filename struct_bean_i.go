@@ -1,12 +1,22 @@
 package gospring
 
 type StructBeanI interface {
+	Eager() StructBeanI
 	Factory(fn interface{}, argv ...interface{}) StructBeanI
 	Finalize(fnName string) StructBeanI
 	ID(id string) StructBeanI
 	Init(fnName string) StructBeanI
+	Lazy() StructBeanI
+	OnBean(id string) StructBeanI
+	OnBeanType(i interface{}) StructBeanI
+	OnMissingBean(id string) StructBeanI
+	OnMissingBeanType(i interface{}) StructBeanI
+	OnProperty(key string, values ...string) StructBeanI
+	Profile(expressions ...string) StructBeanI
 	Property(name string, values ...interface{}) StructBeanI
 	Prototype() StructBeanI
+	Scope(scope Scope) StructBeanI
 	Singleton() StructBeanI
 	TypeOf(i interface{}) StructBeanI
+	When(fn func() bool) StructBeanI
 }
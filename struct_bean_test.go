@@ -28,3 +28,29 @@ func Test_structBeanGetProperty(t *testing.T) {
 	// assert
 	assert.Nil(t, actual)
 }
+
+func Test_structBeanProperty_entries(t *testing.T) {
+	// arrange
+	bean := Bean(struct{}{}).(*structBean)
+
+	// action
+	bean.Property("M", Entry("a", 1), Entry("b", 2))
+
+	// assert
+	entries := bean.GetEntries("M")
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "a", entries[0].GetKey())
+	assert.Len(t, bean.GetProperty("M"), 2)
+	assert.Implements(t, new(ValueBeanI), bean.GetProperty("M")[0])
+}
+
+func Test_structBeanProperty_noEntries(t *testing.T) {
+	// arrange
+	bean := Bean(struct{}{}).(*structBean)
+
+	// action
+	bean.Property("M", Entry("a", 1)).Property("M", 1)
+
+	// assert
+	assert.Nil(t, bean.GetEntries("M"))
+}
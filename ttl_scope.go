@@ -0,0 +1,181 @@
+package gospring
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+type ttlScope struct {
+	ttl   time.Duration
+	clock ClockI
+
+	// lock guards entries and errors
+	lock    sync.Mutex
+	entries map[string]*ttlEntry
+
+	// errors are returned by finalizers of expired objects, which are
+	// returned by Finalize()
+	errors []error
+}
+
+// ttlEntry is the object of a bean. lock is held while the object is
+// created, so it's created once even if many goroutines ask for it.
+type ttlEntry struct {
+	lock     sync.Mutex
+	object   interface{}
+	expires  time.Time
+	callback func() error
+}
+
+// NewTTLScope creates a scope where an object is kept for the duration
+// after it's created. The object is acquired again after it expires, which
+// is created by the factory, properties and the initializer of the bean as
+// usual, and the expired one is finalized once it's replaced.
+//
+// ctx, e := Context().
+//     Scope("token", NewTTLScope(time.Minute)).
+//     Build(
+//         Bean(Token{}).ID("token").Scope("token").Factory(NewToken),
+//     )
+//
+// If the finalizer of an expired object fails, Get(...) still returns the
+// new object, and the error is returned by Finalize().
+func NewTTLScope(ttl time.Duration) TTLScopeI {
+	return &ttlScope{
+		ttl:     ttl,
+		clock:   SystemClock(),
+		entries: make(map[string]*ttlEntry),
+	}
+}
+
+func (scope *ttlScope) Clock(clock ClockI) TTLScopeI {
+	scope.clock = clock
+	return scope
+}
+
+func (scope *ttlScope) Get(name string, create func() (interface{}, error)) (interface{}, error) {
+
+	scope.lock.Lock()
+	entry, present := scope.entries[name]
+	if !present {
+		entry = &ttlEntry{}
+		scope.entries[name] = entry
+	}
+	scope.lock.Unlock()
+
+	// beans depending on each other have different names, so there is no
+	// dead lock while creating them
+	entry.lock.Lock()
+
+	if object := entry.object; object != nil && scope.clock.Now().Before(entry.expires) {
+		entry.lock.Unlock()
+		return object, nil
+	}
+
+	object, e := create()
+	if e != nil {
+		entry.lock.Unlock()
+		return nil, e
+	}
+
+	var callback func() error
+	if entry.object != nil {
+		callback = entry.callback
+	}
+
+	entry.object = object
+	entry.expires = scope.clock.Now().Add(scope.ttl)
+	entry.callback = nil
+	entry.lock.Unlock()
+
+	// the expired object is finalized without the lock, since its finalizer
+	// may get the bean again
+	if callback != nil {
+		if e := callback(); e != nil {
+			scope.lock.Lock()
+			scope.errors = append(scope.errors,
+				fmt.Errorf("Can't finalize the expired object of [%v]. Caused by: %w", name, e))
+			scope.lock.Unlock()
+		}
+	}
+
+	return object, nil
+}
+
+func (scope *ttlScope) Remove(name string) interface{} {
+
+	scope.lock.Lock()
+	entry, present := scope.entries[name]
+	delete(scope.entries, name)
+	scope.lock.Unlock()
+
+	if !present {
+		return nil
+	}
+
+	entry.lock.Lock()
+	defer entry.lock.Unlock()
+
+	object := entry.object
+	entry.object = nil
+	entry.callback = nil
+	return object
+}
+
+// RegisterDestructionCallback ignores objects which are replaced or removed
+// already.
+func (scope *ttlScope) RegisterDestructionCallback(name string, object interface{}, callback func() error) error {
+
+	scope.lock.Lock()
+	entry, present := scope.entries[name]
+	scope.lock.Unlock()
+
+	if !present {
+		return nil
+	}
+
+	entry.lock.Lock()
+	defer entry.lock.Unlock()
+
+	if entry.object != nil && isSameObject(entry.object, object) {
+		entry.callback = callback
+	}
+	return nil
+}
+
+// Finalize calls finalizers of all objects even if some fail, and returns
+// the first error, including errors of expired objects finalized before.
+// Finalizers are called without locks, since they may get other beans.
+func (scope *ttlScope) Finalize() error {
+
+	scope.lock.Lock()
+	entries := scope.entries
+	errs := scope.errors
+	scope.entries = make(map[string]*ttlEntry)
+	scope.errors = nil
+	scope.lock.Unlock()
+
+	var first error
+	if len(errs) > 0 {
+		first = errs[0]
+	}
+
+	for name, entry := range entries {
+		entry.lock.Lock()
+		object := entry.object
+		callback := entry.callback
+		entry.object = nil
+		entry.callback = nil
+		entry.lock.Unlock()
+
+		if object == nil || callback == nil {
+			continue
+		}
+		if e := callback(); e != nil && first == nil {
+			first = fmt.Errorf("Can't finalize bean [%v] in the TTL scope. Caused by: %w", name, e)
+		}
+	}
+
+	return first
+}
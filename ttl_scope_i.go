@@ -0,0 +1,13 @@
+package gospring
+
+// TTLScopeI is a scope whose objects are recreated after a duration.
+type TTLScopeI interface {
+	ScopeI
+
+	// Clock replaces the clock which tells whether objects expire.
+	Clock(clock ClockI) TTLScopeI
+
+	// Finalize finalizes and removes all objects in the scope. Errors of
+	// finalizing expired objects before are returned as well.
+	Finalize() error
+}
@@ -0,0 +1,250 @@
+package gospring
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Test_ttl_clock struct {
+	lock sync.Mutex
+	now  time.Time
+}
+
+func (clock *Test_ttl_clock) Now() time.Time {
+	clock.lock.Lock()
+	defer clock.lock.Unlock()
+	return clock.now
+}
+
+func (clock *Test_ttl_clock) add(d time.Duration) {
+	clock.lock.Lock()
+	defer clock.lock.Unlock()
+	clock.now = clock.now.Add(d)
+}
+
+type Test_ttl_struct struct {
+	Name        string
+	Serial      int
+	initialized bool
+	finalized   bool
+}
+
+var Test_ttl_serial = 0
+
+func newTest_ttl_struct() *Test_ttl_struct {
+	Test_ttl_serial++
+	return &Test_ttl_struct{Serial: Test_ttl_serial}
+}
+
+func (s *Test_ttl_struct) Init() {
+	s.initialized = true
+}
+
+func (s *Test_ttl_struct) Finalize() {
+	s.finalized = true
+}
+
+func (s *Test_ttl_struct) Fail() error {
+	return errors.New("failed")
+}
+
+var Test_ttl_context ApplicationContextI
+
+type Test_ttl_getter struct {
+	other interface{}
+}
+
+func (s *Test_ttl_getter) Finalize() error {
+	other, e := Test_ttl_context.GetBean("1")
+	s.other = other
+	return e
+}
+
+func Test_TTLScope(t *testing.T) {
+	// arrange
+	clock := &Test_ttl_clock{now: time.Unix(0, 0)}
+	ctx, e := Context().Scope("ttl", NewTTLScope(time.Minute).Clock(clock)).Build(Beans(
+		Bean(Test_ttl_struct{}).ID("1").Scope("ttl").Factory(newTest_ttl_struct).Property("Name", "a"),
+	)...)
+	require.Nil(t, e)
+
+	// action
+	bean1, e1 := ctx.GetBean("1")
+	clock.add(59 * time.Second)
+	bean2, e2 := ctx.GetBean("1")
+	clock.add(time.Second)
+	bean3, e3 := ctx.GetBean("1")
+
+	// assert
+	require.Nil(t, e1)
+	require.Nil(t, e2)
+	require.Nil(t, e3)
+	assert.True(t, bean1 == bean2)
+	assert.False(t, bean1 == bean3)
+	assert.True(t, bean1.(*Test_ttl_struct).finalized)
+	assert.Equal(t, bean1.(*Test_ttl_struct).Serial+1, bean3.(*Test_ttl_struct).Serial)
+	assert.Equal(t, "a", bean3.(*Test_ttl_struct).Name)
+	assert.True(t, bean3.(*Test_ttl_struct).initialized)
+	assert.False(t, bean3.(*Test_ttl_struct).finalized)
+}
+
+func Test_TTLScope_concurrent(t *testing.T) {
+	// arrange
+	clock := &Test_ttl_clock{now: time.Unix(0, 0)}
+	ctx, e := Context().Scope("ttl", NewTTLScope(time.Minute).Clock(clock)).Build(Beans(
+		Bean(Test_ttl_struct{}).ID("1").Scope("ttl").Factory(newTest_ttl_struct).Property("Name", "a"),
+	)...)
+	require.Nil(t, e)
+	beans := make([]interface{}, 20)
+	errs := make([]error, 20)
+
+	// action
+	var wg sync.WaitGroup
+	for i := range beans {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			beans[i], errs[i] = ctx.GetBean("1")
+		}(i)
+	}
+	wg.Wait()
+
+	// assert
+	for i := range beans {
+		require.Nil(t, errs[i])
+		assert.True(t, beans[i] == beans[0])
+	}
+}
+
+func Test_TTLScope_remove(t *testing.T) {
+	// arrange
+	scope := NewTTLScope(time.Minute)
+	ctx, e := Context().Scope("ttl", scope).Build(Beans(
+		Bean(Test_ttl_struct{}).ID("1").Scope("ttl").Factory(newTest_ttl_struct).Property("Name", "a"),
+	)...)
+	require.Nil(t, e)
+	bean1, e := ctx.GetBean("1")
+	require.Nil(t, e)
+
+	// action
+	removed := scope.Remove("1")
+	bean2, e := ctx.GetBean("1")
+
+	// assert
+	require.Nil(t, e)
+	assert.True(t, removed == bean1)
+	assert.False(t, bean1 == bean2)
+	assert.False(t, bean1.(*Test_ttl_struct).finalized)
+}
+
+func Test_TTLScope_Finalize(t *testing.T) {
+	// arrange
+	scope := NewTTLScope(time.Minute)
+	ctx, e := Context().Scope("ttl", scope).Build(Beans(
+		Bean(Test_ttl_struct{}).ID("1").Scope("ttl").Factory(newTest_ttl_struct).Property("Name", "a"),
+	)...)
+	require.Nil(t, e)
+	bean1, e := ctx.GetBean("1")
+	require.Nil(t, e)
+
+	// action
+	e = scope.Finalize()
+	bean2, e2 := ctx.GetBean("1")
+
+	// assert
+	require.Nil(t, e)
+	require.Nil(t, e2)
+	assert.True(t, bean1.(*Test_ttl_struct).finalized)
+	assert.False(t, bean1 == bean2)
+}
+
+func Test_TTLScope_finalizeFailed(t *testing.T) {
+	// arrange
+	clock := &Test_ttl_clock{now: time.Unix(0, 0)}
+	scope := NewTTLScope(time.Minute).Clock(clock)
+	ctx, e := Context().Scope("ttl", scope).Build(Beans(
+		Bean(Test_ttl_struct{}).ID("1").Scope("ttl").Finalize("Fail"),
+	)...)
+	require.Nil(t, e)
+	bean1, e := ctx.GetBean("1")
+	require.Nil(t, e)
+	clock.add(time.Minute)
+
+	// action
+	bean2, e2 := ctx.GetBean("1")
+	bean3, e3 := ctx.GetBean("1")
+	ef := scope.Finalize()
+
+	// assert
+	require.Nil(t, e2)
+	require.Nil(t, e3)
+	assert.False(t, bean1 == bean2)
+	assert.True(t, bean2 == bean3)
+	require.NotNil(t, ef)
+	assert.Contains(t, ef.Error(), "Can't finalize the expired object of [1]")
+}
+
+func Test_TTLScope_finalizerGetsBean(t *testing.T) {
+	// arrange
+	clock := &Test_ttl_clock{now: time.Unix(0, 0)}
+	ctx, e := Context().Scope("ttl", NewTTLScope(time.Minute).Clock(clock)).Build(Beans(
+		Bean(Test_ttl_getter{}).ID("1").Scope("ttl"),
+	)...)
+	require.Nil(t, e)
+	Test_ttl_context = ctx
+	bean1, e := ctx.GetBean("1")
+	require.Nil(t, e)
+	clock.add(time.Minute)
+
+	// action
+	done := make(chan error)
+	var bean2 interface{}
+	go func() {
+		var e error
+		bean2, e = ctx.GetBean("1")
+		done <- e
+	}()
+
+	// assert
+	select {
+	case e2 := <-done:
+		require.Nil(t, e2)
+		assert.True(t, bean1.(*Test_ttl_getter).other == bean2)
+	case <-time.After(time.Second):
+		assert.Fail(t, "a finalizer getting the bean is blocked")
+	}
+}
+
+func Test_TTLScope_FinalizeGetsBean(t *testing.T) {
+	// arrange
+	scope := NewTTLScope(time.Minute)
+	ctx, e := Context().Scope("ttl", scope).Build(Beans(
+		Bean(Test_ttl_getter{}).ID("1").Scope("ttl"),
+	)...)
+	require.Nil(t, e)
+	Test_ttl_context = ctx
+	bean1, e := ctx.GetBean("1")
+	require.Nil(t, e)
+
+	// action
+	done := make(chan error)
+	go func() {
+		done <- scope.Finalize()
+	}()
+
+	// assert
+	select {
+	case e := <-done:
+		require.Nil(t, e)
+		other := bean1.(*Test_ttl_getter).other
+		assert.NotNil(t, other)
+		assert.False(t, bean1 == other)
+	case <-time.After(time.Second):
+		assert.Fail(t, "a finalizer getting the bean is blocked")
+	}
+}
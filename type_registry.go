@@ -0,0 +1,102 @@
+package gospring
+
+import (
+	"reflect"
+	"sort"
+	"time"
+)
+
+type typeRegistry struct {
+	types     map[string]reflect.Type
+	factories map[string]interface{}
+}
+
+// NewTypeRegistry creates a registry which maps names in bean definition
+// files to Go types and factory functions. Basic types like "int",
+// "string" and "time.Duration" are registered already.
+//
+// registry := NewTypeRegistry().
+//     Type("Astruct", Astruct{}).
+//     Factory("NewAstruct", NewAstruct)
+func NewTypeRegistry() TypeRegistryI {
+	registry := &typeRegistry{
+		types:     make(map[string]reflect.Type),
+		factories: make(map[string]interface{}),
+	}
+
+	for _, i := range []interface{}{
+		false, "",
+		int(0), int8(0), int16(0), int32(0), int64(0),
+		uint(0), uint8(0), uint16(0), uint32(0), uint64(0),
+		float32(0), float64(0),
+		time.Duration(0),
+	} {
+		registry.Type(reflect.TypeOf(i).String(), i)
+	}
+
+	return registry
+}
+
+// Type registers the type of i with the name. If i is a pointer, the type
+// it points to is registered.
+func (registry *typeRegistry) Type(name string, i interface{}) TypeRegistryI {
+	tvpe := reflect.TypeOf(i)
+	if tvpe.Kind() == reflect.Ptr {
+		tvpe = tvpe.Elem()
+	}
+	registry.types[name] = tvpe
+	return registry
+}
+
+func (registry *typeRegistry) Factory(name string, fn interface{}) TypeRegistryI {
+	registry.factories[name] = fn
+	return registry
+}
+
+func (registry *typeRegistry) GetType(name string) (reflect.Type, bool) {
+	tvpe, present := registry.types[name]
+	return tvpe, present
+}
+
+func (registry *typeRegistry) GetFactory(name string) (interface{}, bool) {
+	fn, present := registry.factories[name]
+	return fn, present
+}
+
+// GetTypeName returns the name of the type. If the type is registered with
+// several names, the first one in lexical order is returned.
+func (registry *typeRegistry) GetTypeName(tvpe reflect.Type) (string, bool) {
+	names := []string{}
+	for name, t := range registry.types {
+		if t == tvpe {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return "", false
+	}
+	sort.Strings(names)
+	return names[0], true
+}
+
+// GetFactoryName returns the name of the factory function. If the function
+// is registered with several names, the first one in lexical order is
+// returned.
+func (registry *typeRegistry) GetFactoryName(fn interface{}) (string, bool) {
+	value := reflect.ValueOf(fn)
+	if value.Kind() != reflect.Func {
+		return "", false
+	}
+	names := []string{}
+	for name, f := range registry.factories {
+		v := reflect.ValueOf(f)
+		if v.Kind() == reflect.Func && v.Pointer() == value.Pointer() {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return "", false
+	}
+	sort.Strings(names)
+	return names[0], true
+}
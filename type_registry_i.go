@@ -0,0 +1,12 @@
+package gospring
+
+import "reflect"
+
+type TypeRegistryI interface {
+	Factory(name string, fn interface{}) TypeRegistryI
+	GetFactory(name string) (interface{}, bool)
+	GetFactoryName(fn interface{}) (string, bool)
+	GetType(name string) (reflect.Type, bool)
+	GetTypeName(tvpe reflect.Type) (string, bool)
+	Type(name string, i interface{}) TypeRegistryI
+}
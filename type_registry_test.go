@@ -0,0 +1,76 @@
+package gospring
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_NewTypeRegistry_basicTypes(t *testing.T) {
+	// arrange
+	registry := NewTypeRegistry()
+
+	// action
+	i, iok := registry.GetType("int")
+	d, dok := registry.GetType("time.Duration")
+
+	// assert
+	assert.True(t, iok)
+	assert.Equal(t, reflect.TypeOf(0), i)
+	assert.True(t, dok)
+	assert.Equal(t, reflect.TypeOf(time.Second), d)
+}
+
+func Test_TypeRegistry_pointer(t *testing.T) {
+	// arrange
+	type beanStruct struct{}
+	registry := NewTypeRegistry()
+
+	// action
+	registry.Type("a", &beanStruct{})
+
+	// assert
+	tvpe, ok := registry.GetType("a")
+	assert.True(t, ok)
+	assert.Equal(t, reflect.TypeOf(beanStruct{}), tvpe)
+}
+
+func Test_TypeRegistry_factory(t *testing.T) {
+	// arrange
+	fn := func() int { return 1 }
+	registry := NewTypeRegistry()
+
+	// action
+	registry.Factory("fn", fn)
+
+	// assert
+	actual, ok := registry.GetFactory("fn")
+	assert.True(t, ok)
+	assert.Equal(t, 1, actual.(func() int)())
+	_, ok = registry.GetFactory("aaa")
+	assert.False(t, ok)
+}
+
+func Test_TypeRegistry_names(t *testing.T) {
+	// arrange
+	type beanStruct struct{}
+	fn := func() int { return 1 }
+	registry := NewTypeRegistry().
+		Type("b", beanStruct{}).
+		Type("a", beanStruct{}).
+		Factory("fn", fn)
+
+	// action
+	typeName, typeOk := registry.GetTypeName(reflect.TypeOf(beanStruct{}))
+	factoryName, factoryOk := registry.GetFactoryName(fn)
+	_, missingOk := registry.GetFactoryName(func() {})
+
+	// assert
+	assert.True(t, typeOk)
+	assert.Equal(t, "a", typeName)
+	assert.True(t, factoryOk)
+	assert.Equal(t, "fn", factoryName)
+	assert.False(t, missingOk)
+}
@@ -0,0 +1,369 @@
+package gospring
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// validate checks beans without creating them, and returns all problems
+// found. Beans in skipped, e.g. whose references can't be resolved, aren't
+// checked.
+func (ctx *applicationContext) validate(skipped map[BeanI]bool) []error {
+
+	v := validator{
+		ctx:     ctx,
+		visited: make(map[BeanI]bool),
+	}
+	for bean := range skipped {
+		v.visited[bean] = true
+	}
+
+	for _, bean := range ctx.beans {
+		v.validateBean(bean, nil)
+	}
+
+	return v.problems
+}
+
+type validator struct {
+	ctx      *applicationContext
+	visited  map[BeanI]bool
+	problems []error
+}
+
+func (v *validator) report(path resolutionPath, format string, a ...interface{}) {
+	v.problems = append(v.problems, fmt.Errorf("%v: %s", path, fmt.Sprintf(format, a...)))
+}
+
+func (v *validator) validateBean(bean BeanI, path resolutionPath) {
+
+	sbean, ok := bean.(*structBean)
+	if !ok || v.visited[bean] {
+		return
+	}
+	v.visited[bean] = true
+
+	step := resolutionStep(sbean)
+	instance := instanceType(sbean)
+
+	v.validateMethod(path.with(step), instance, sbean.GetInit(), DefaultInitFunc, "initializer")
+	v.validateMethod(path.with(step), instance, sbean.GetFinalize(), DefaultFinalizeFunc, "finalizer")
+
+	if fn, argvs := sbean.GetFactory(); fn != nil {
+		fnType := reflect.TypeOf(fn)
+		for i, argv := range argvs {
+			argPath := path.with(fmt.Sprintf("%s.factory arg #%d", step, i))
+			v.validateValue(sbean, argPath, argv, fnType.In(i))
+		}
+	}
+
+	properties := v.ctx.getProperties(sbean)
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		v.validateProperty(sbean, name, properties[name], path.with(step+"."+name))
+	}
+}
+
+func (v *validator) validateProperty(bean *structBean, name string, ps []BeanI, path resolutionPath) {
+
+	tvpe := bean.GetType()
+	entries := bean.GetEntries(name)
+
+	property, e := newPropertyPath(tvpe, name)
+	if e != nil {
+		v.report(path, "%v", e)
+		return
+	}
+	if len(ps) == 0 {
+		v.report(path, "no value is given")
+		return
+	}
+
+	fieldType := property.tvpe
+
+	switch fieldType.Kind() {
+	case reflect.Slice:
+		if entries != nil {
+			v.report(path, "entries can't be injected into a slice field")
+			return
+		}
+		for i, p := range ps {
+			v.validateValue(bean, path.index(i), p, fieldType.Elem())
+		}
+
+	case reflect.Map:
+		if entries == nil {
+			if len(ps) > 1 {
+				v.report(path, "[%d] values are given to a map field without Entry(...)", len(ps))
+				return
+			}
+			v.validateValue(bean, path, ps[0], fieldType)
+			return
+		}
+		keys := make(map[interface{}]bool)
+		for i, p := range ps {
+			if entries[i] == nil {
+				v.report(path.index(i), "the value isn't an entry")
+				continue
+			}
+			key := reflect.ValueOf(entries[i].GetKey())
+			if !key.IsValid() {
+				v.report(path.index(i), "the key is nil")
+				continue
+			}
+			converted, ok := convertValue(key, fieldType.Key())
+			if !ok {
+				v.report(path.index(key.Interface()), "key [%v] can't be converted to [%v]",
+					key.Interface(), fieldType.Key())
+				continue
+			}
+			k := converted.Interface()
+			if keys[k] {
+				v.report(path.index(k), "key [%v] is duplicated", k)
+				continue
+			}
+			keys[k] = true
+			v.validateValue(bean, path.index(k), p, fieldType.Elem())
+		}
+
+	default:
+		if entries != nil {
+			v.report(path, "entries can't be injected into a non-map field")
+			return
+		}
+		if len(ps) > 1 {
+			v.report(path, "[%d] values are given to a field which isn't a slice", len(ps))
+			return
+		}
+		v.validateValue(bean, path, ps[0], fieldType)
+	}
+}
+
+// validateValue checks whether the bean can be injected into the type of the
+// owner, in the same way as inject(...) does.
+func (v *validator) validateValue(owner BeanI, path resolutionPath, bean BeanI, toType reflect.Type) {
+
+	if _, ok, e := v.ctx.getConfigValue(bean, toType); ok {
+		if e != nil {
+			v.report(path.with(resolutionStep(bean)), "%v", e)
+		}
+		return
+	}
+
+	if vbean, ok := bean.(*valueBean); ok && vbean.value == nil {
+		v.report(path.with(resolutionStep(bean)), "nil can't be injected")
+		return
+	}
+
+	v.validateBean(bean, path)
+
+	// a singleton is created out of any request, so are prototypes created
+	// with it
+	if isSingleton(owner.GetScope()) {
+		if requestPath := v.requestPath(bean, path); requestPath != nil {
+			v.report(requestPath, "a request-scoped bean can't be injected into a singleton")
+			return
+		}
+	}
+
+	// nobody releases an object held by another bean
+	if _, ok := v.ctx.scopes[bean.GetScope()].(PoolScopeI); ok {
+		v.report(path.with(resolutionStep(bean)), "a pool-scoped bean can't be injected, since it's never released")
+		return
+	}
+
+	fromType := instanceType(bean)
+	if fromType == nil {
+		// unknown until the bean is created
+		return
+	}
+
+	if isConvertible(bean, fromType, toType) {
+		return
+	}
+
+	if fromType.Kind() == reflect.Ptr && isConvertibleElem(bean, fromType, toType) {
+		if Singleton == bean.GetScope() {
+			v.report(path.with(resolutionStep(bean)), "a singleton can't be injected into non-pointer [%v]", toType)
+		}
+		return
+	}
+
+	v.report(path.with(resolutionStep(bean)), "[%v] can't be injected into [%v]", fromType, toType)
+}
+
+// requestPath returns the path to a request-scoped bean which is created
+// with the bean, i.e. the bean itself or one which prototypes created with
+// the bean depend on, or nil if there isn't.
+func (v *validator) requestPath(bean BeanI, path resolutionPath) resolutionPath {
+
+	switch bean.GetScope() {
+	case Request:
+		return path.with(resolutionStep(bean))
+	case Prototype:
+	default:
+		return nil
+	}
+
+	sbean, ok := bean.(*structBean)
+	if ref, isRef := bean.(ReferenceBeanI); isRef {
+		sbean, ok = ref.GetReference().(*structBean)
+	}
+	if !ok {
+		return nil
+	}
+	step := resolutionStep(sbean)
+
+	if _, argvs := sbean.GetFactory(); argvs != nil {
+		for i, argv := range argvs {
+			if p := v.requestPath(argv, path.with(fmt.Sprintf("%s.factory arg #%d", step, i))); p != nil {
+				return p
+			}
+		}
+	}
+
+	properties := v.ctx.getProperties(sbean)
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		ps := properties[name]
+		entries := sbean.GetEntries(name)
+		for i, p := range ps {
+			propertyPath := path.with(step + "." + name)
+			switch {
+			case entries != nil && entries[i] != nil:
+				propertyPath = propertyPath.index(entries[i].GetKey())
+			case len(ps) > 1:
+				propertyPath = propertyPath.index(i)
+			}
+			if requestPath := v.requestPath(p, propertyPath); requestPath != nil {
+				return requestPath
+			}
+		}
+	}
+
+	return nil
+}
+
+// isConvertible checks whether the instance of the bean can be converted into
+// the type by inject(...). Literals are checked with their values, e.g. 80
+// can be injected into an int8 but 80.9 can't.
+func isConvertible(bean BeanI, fromType, toType reflect.Type) bool {
+	if value, ok := literalOf(bean); ok {
+		ptr := reflect.New(value.Type())
+		ptr.Elem().Set(value)
+		_, ok = convertValue(ptr, toType)
+		return ok
+	}
+	return isConvertibleType(fromType, toType)
+}
+
+// isConvertibleElem is the same as isConvertible but for the value which the
+// instance points to.
+func isConvertibleElem(bean BeanI, fromType, toType reflect.Type) bool {
+	if value, ok := literalOf(bean); ok {
+		_, ok = convertValue(value, toType)
+		return ok
+	}
+	return isConvertibleType(fromType.Elem(), toType)
+}
+
+// literalOf returns the value of a literal bean.
+func literalOf(bean BeanI) (reflect.Value, bool) {
+	if vbean, ok := bean.(*valueBean); ok && vbean.value != nil {
+		return reflect.ValueOf(vbean.value), true
+	}
+	return reflect.Value{}, false
+}
+
+// validateMethod checks the initializer or the finalizer. The method with
+// the default name is optional.
+func (v *validator) validateMethod(path resolutionPath, instance reflect.Type, name *string, defaultName string, kind string) {
+
+	if instance == nil {
+		return
+	}
+
+	methodName := defaultName
+	if name != nil {
+		methodName = *name
+	}
+
+	method, ok := instance.MethodByName(methodName)
+	if !ok {
+		if name != nil {
+			v.report(path, "%s [%s] doesn't exist in [%v]", kind, methodName, instance)
+		}
+		return
+	}
+
+	// the receiver is the first input
+	fnType := method.Type
+	if fnType.NumIn() != 1 {
+		v.report(path, "%s [%s] can't have parameters", kind, methodName)
+	}
+
+	switch fnType.NumOut() {
+	case 0:
+	case 1:
+		if fnType.Out(0) != reflect.TypeOf((*error)(nil)).Elem() {
+			v.report(path, "%s [%s] can only return an error instead of [%v]", kind, methodName, fnType.Out(0))
+		}
+	default:
+		v.report(path, "%s [%s] can only return an error instead of [%d] values", kind, methodName, fnType.NumOut())
+	}
+}
+
+// instanceType returns the type of the instance created by the bean, or
+// nil if it's unknown until the bean is created, e.g. the factory returns
+// an interface.
+func instanceType(bean BeanI) reflect.Type {
+
+	switch b := bean.(type) {
+	case ReferenceBeanI:
+		if target := b.GetReference(); target != nil {
+			return instanceType(target)
+		}
+		return nil
+
+	case *parentBean:
+		if definition := b.definition(); definition != nil {
+			return instanceType(definition)
+		}
+		return nil
+
+	case *valueBean:
+		if b.value == nil {
+			return nil
+		}
+		return reflect.PtrTo(b.GetType())
+
+	case *structBean:
+		switch {
+		case b.GetBuffer() != nil:
+			return b.GetType()
+		case b.HasFactory():
+			fn, _ := b.GetFactory()
+			out := reflect.TypeOf(fn).Out(0)
+			if out.Kind() == reflect.Interface {
+				return nil
+			}
+			return out
+		default:
+			return reflect.PtrTo(b.GetType())
+		}
+
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,370 @@
+package gospring
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Test_validate_struct1 struct {
+	Name    string
+	Count   int
+	Next    *Test_validate_struct2
+	Nexts   []*Test_validate_struct2
+	Labels  map[string]string
+	Counts  map[int]string
+	private string
+}
+
+type Test_validate_struct2 struct {
+	Value Test_validate_struct1
+}
+
+var Test_validate_created = 0
+
+func (s *Test_validate_struct2) Init() {
+	Test_validate_created++
+}
+
+func (s *Test_validate_struct2) Broken(a int) string {
+	return ""
+}
+
+func Test_validate_fieldNotExist(t *testing.T) {
+	// arrange
+	beans := Beans(
+		Bean(Test_validate_struct1{}).ID("1").Property("Unknown", "aaa"),
+	)
+
+	// action
+	ctx, e := NewApplicationContext(beans...)
+
+	// assert
+	assert.Nil(t, ctx)
+	var ve *ValidationError
+	require.True(t, errors.As(e, &ve))
+	require.Len(t, ve.Problems, 1)
+	assert.Equal(t, "1[gospring.Test_validate_struct1].Unknown: "+
+		"type [gospring.Test_validate_struct1] has no field [Unknown]", ve.Problems[0].Error())
+}
+
+func Test_validate_unexportedField(t *testing.T) {
+	// arrange
+	beans := Beans(
+		Bean(Test_validate_struct1{}).ID("1").Property("private", "aaa"),
+	)
+
+	// action
+	e := Context().Validate(beans...)
+
+	// assert
+	var ve *ValidationError
+	require.True(t, errors.As(e, &ve))
+	require.Len(t, ve.Problems, 1)
+	assert.Contains(t, ve.Problems[0].Error(), "field [private] is unexported")
+}
+
+func Test_validate_typeMismatch(t *testing.T) {
+	// arrange
+	beans := Beans(
+		Bean(Test_validate_struct1{}).ID("1").Property("Next", Ref("2")),
+		Bean(Test_validate_struct1{}).ID("2"),
+	)
+
+	// action
+	e := Context().Validate(beans...)
+
+	// assert
+	var ve *ValidationError
+	require.True(t, errors.As(e, &ve))
+	require.Len(t, ve.Problems, 1)
+	assert.Equal(t, "1[gospring.Test_validate_struct1].Next -> 2[gospring.Test_validate_struct1]: "+
+		"[*gospring.Test_validate_struct1] can't be injected into [*gospring.Test_validate_struct2]",
+		ve.Problems[0].Error())
+}
+
+func Test_validate_lossyConversion(t *testing.T) {
+	// arrange
+	beans := Beans(
+		Bean(Test_validate_struct1{}).ID("1").
+			Property("Name", 65).
+			Property("Count", 80.9).
+			Property("Counts", Entry(1.5, "a")),
+		Bean(Test_validate_struct1{}).ID("2").
+			Property("Count", 80.0).
+			Property("Counts", Entry(int8(1), "a")),
+	)
+
+	// action
+	e := Context().Validate(beans...)
+
+	// assert
+	var ve *ValidationError
+	require.True(t, errors.As(e, &ve))
+	require.Len(t, ve.Problems, 3)
+	assert.Equal(t, "1[gospring.Test_validate_struct1].Count -> (value float64): "+
+		"[*float64] can't be injected into [int]",
+		ve.Problems[0].Error())
+	assert.Equal(t, "1[gospring.Test_validate_struct1].Counts[1.5]: "+
+		"key [1.5] can't be converted to [int]",
+		ve.Problems[1].Error())
+	assert.Equal(t, "1[gospring.Test_validate_struct1].Name -> (value int): "+
+		"[*int] can't be injected into [string]",
+		ve.Problems[2].Error())
+}
+
+func Test_validate_singletonToNonPointer(t *testing.T) {
+	// arrange
+	beans := Beans(
+		Bean(Test_validate_struct2{}).ID("1").Property("Value", Ref("2")),
+		Bean(Test_validate_struct1{}).ID("2").Singleton(),
+	)
+
+	// action
+	e := Context().Validate(beans...)
+
+	// assert
+	var ve *ValidationError
+	require.True(t, errors.As(e, &ve))
+	require.Len(t, ve.Problems, 1)
+	assert.Contains(t, ve.Problems[0].Error(), "a singleton can't be injected into non-pointer")
+}
+
+func Test_validate_parentSingletonToNonPointer(t *testing.T) {
+	// arrange
+	parent, e := NewApplicationContext(Beans(
+		Bean(Test_validate_struct1{}).ID("p").Singleton(),
+	)...)
+	require.Nil(t, e)
+
+	// action
+	e = Context().Parent(parent).Validate(Beans(
+		Bean(Test_validate_struct2{}).ID("1").Property("Value", Ref("p")),
+	)...)
+
+	// assert
+	var ve *ValidationError
+	require.True(t, errors.As(e, &ve))
+	require.Len(t, ve.Problems, 1)
+	assert.Contains(t, ve.Problems[0].Error(), "a singleton can't be injected into non-pointer")
+}
+
+func Test_validate_requestToSingleton(t *testing.T) {
+	// arrange
+	beans := Beans(
+		Bean(Test_validate_struct1{}).ID("1").Property("Next", Ref("3")),
+		Bean(Test_validate_struct1{}).ID("2").Singleton().Property("Nexts", Ref("3")),
+		Bean(Test_validate_struct2{}).ID("3").Scope(Request),
+		Bean(Test_validate_struct1{}).ID("4").Prototype().Property("Next", Ref("3")),
+	)
+
+	// action
+	e := Context().Validate(beans...)
+
+	// assert
+	var ve *ValidationError
+	require.True(t, errors.As(e, &ve))
+	require.Len(t, ve.Problems, 2)
+	assert.Equal(t, "1[gospring.Test_validate_struct1].Next -> 3[gospring.Test_validate_struct2]: "+
+		"a request-scoped bean can't be injected into a singleton", ve.Problems[0].Error())
+	assert.Equal(t, "2[gospring.Test_validate_struct1].Nexts[0] -> 3[gospring.Test_validate_struct2]: "+
+		"a request-scoped bean can't be injected into a singleton", ve.Problems[1].Error())
+}
+
+func Test_validate_requestToSingletonThroughPrototype(t *testing.T) {
+	// arrange
+	beans := Beans(
+		Bean(Test_validate_struct1{}).ID("1").Property("Next", Ref("2")),
+		Bean(Test_validate_struct2{}).ID("2").Prototype().Property("Value", Ref("3")),
+		Bean(Test_validate_struct1{}).ID("3").Scope(Request),
+	)
+
+	// action
+	e := Context().Validate(beans...)
+
+	// assert
+	var ve *ValidationError
+	require.True(t, errors.As(e, &ve))
+	require.Len(t, ve.Problems, 1)
+	assert.Equal(t, "1[gospring.Test_validate_struct1].Next -> 2[gospring.Test_validate_struct2].Value -> "+
+		"3[gospring.Test_validate_struct1]: "+
+		"a request-scoped bean can't be injected into a singleton", ve.Problems[0].Error())
+}
+
+func Test_validate_poolScope(t *testing.T) {
+	// arrange
+	beans := Beans(
+		Bean(Test_validate_struct1{}).ID("1").Prototype().Property("Next", Ref("2")),
+		Bean(Test_validate_struct2{}).ID("2").Scope("pool"),
+	)
+
+	// action
+	e := Context().Scope("pool", NewPoolScope(0, 1)).Validate(beans...)
+
+	// assert
+	var ve *ValidationError
+	require.True(t, errors.As(e, &ve))
+	require.Len(t, ve.Problems, 1)
+	assert.Equal(t, "1[gospring.Test_validate_struct1].Next -> 2[gospring.Test_validate_struct2]: "+
+		"a pool-scoped bean can't be injected, since it's never released", ve.Problems[0].Error())
+}
+
+func Test_validate_mapEntries(t *testing.T) {
+	// arrange
+	beans := Beans(
+		Bean(Test_validate_struct1{}).ID("1").Property("Counts", Entry("a", "b")),
+		Bean(Test_validate_struct1{}).ID("2").Property("Labels", Entry("a", "b"), Entry("a", "c")),
+		Bean(Test_validate_struct1{}).ID("3").Property("Labels", Entry("a", "b"), "c"),
+		Bean(Test_validate_struct1{}).ID("4").Property("Nexts", Entry("a", "b")),
+	)
+
+	// action
+	e := Context().Validate(beans...)
+
+	// assert
+	var ve *ValidationError
+	require.True(t, errors.As(e, &ve))
+	require.Len(t, ve.Problems, 4)
+	assert.Equal(t, "1[gospring.Test_validate_struct1].Counts[a]: key [a] can't be converted to [int]", ve.Problems[0].Error())
+	assert.Equal(t, "2[gospring.Test_validate_struct1].Labels[a]: key [a] is duplicated", ve.Problems[1].Error())
+	assert.Equal(t, "3[gospring.Test_validate_struct1].Labels[1]: the value isn't an entry", ve.Problems[2].Error())
+	assert.Equal(t, "4[gospring.Test_validate_struct1].Nexts: entries can't be injected into a slice field", ve.Problems[3].Error())
+}
+
+func Test_validate_propertyValue(t *testing.T) {
+	// arrange
+	beans := Beans(
+		Bean(Test_validate_struct1{}).ID("1").Property("Count", Value("count")),
+	)
+
+	// action
+	e := Context().Properties(map[string]string{"count": "aaa"}).Validate(beans...)
+
+	// assert
+	var ve *ValidationError
+	require.True(t, errors.As(e, &ve))
+	require.Len(t, ve.Problems, 1)
+	assert.Contains(t, ve.Problems[0].Error(), "1[gospring.Test_validate_struct1].Count -> (property count): Can't convert [aaa]")
+}
+
+func Test_validate_methods(t *testing.T) {
+	// arrange
+	beans := Beans(
+		Bean(Test_validate_struct2{}).ID("1").Init("Unknown"),
+		Bean(Test_validate_struct2{}).ID("2").Finalize("Broken"),
+	)
+
+	// action
+	e := Context().Validate(beans...)
+
+	// assert
+	var ve *ValidationError
+	require.True(t, errors.As(e, &ve))
+	require.Len(t, ve.Problems, 3)
+	assert.Equal(t, "1[gospring.Test_validate_struct2]: "+
+		"initializer [Unknown] doesn't exist in [*gospring.Test_validate_struct2]", ve.Problems[0].Error())
+	assert.Equal(t, "2[gospring.Test_validate_struct2]: "+
+		"finalizer [Broken] can't have parameters", ve.Problems[1].Error())
+	assert.Equal(t, "2[gospring.Test_validate_struct2]: "+
+		"finalizer [Broken] can only return an error instead of [string]", ve.Problems[2].Error())
+}
+
+func Test_validate_factoryArgument(t *testing.T) {
+	// arrange
+	fn := func(count int) *Test_validate_struct1 {
+		return &Test_validate_struct1{Count: count}
+	}
+	beans := Beans(
+		Bean(Test_validate_struct1{}).ID("1").Factory(fn, "aaa"),
+	)
+
+	// action
+	e := Context().Validate(beans...)
+
+	// assert
+	var ve *ValidationError
+	require.True(t, errors.As(e, &ve))
+	require.Len(t, ve.Problems, 1)
+	assert.Contains(t, ve.Problems[0].Error(), "1[gospring.Test_validate_struct1].factory arg #0 -> (value string): ")
+}
+
+func Test_validate_allProblems(t *testing.T) {
+	// arrange
+	beans := Beans(
+		Bean(Test_validate_struct1{}).ID("1").
+			Property("Unknown", "aaa").
+			Property("Count", "aaa").
+			Property("Nexts",
+				Bean(Test_validate_struct2{}),
+				Bean(Test_validate_struct1{}),
+			),
+		Bean(Test_validate_struct2{}).ID("2").Init("Unknown"),
+	)
+
+	// action
+	e := Context().Validate(beans...)
+
+	// assert
+	var ve *ValidationError
+	require.True(t, errors.As(e, &ve))
+	assert.Len(t, ve.Problems, 4)
+	assert.Contains(t, e.Error(), "Found [4] problems in bean definitions:")
+	assert.Contains(t, e.Error(), "1[gospring.Test_validate_struct1].Nexts[1] -> anonymous[gospring.Test_validate_struct1]: ")
+}
+
+func Test_validate_withReferenceProblem(t *testing.T) {
+	// arrange
+	beans := Beans(
+		Bean(Test_validate_struct1{}).ID("1").Property("Next", Ref("unknown")),
+		Bean(Test_validate_struct1{}).ID("2").Property("Count", "aaa"),
+	)
+
+	// action
+	e := Context().Validate(beans...)
+
+	// assert
+	var ve *ValidationError
+	require.True(t, errors.As(e, &ve))
+	require.Len(t, ve.Problems, 2)
+	assert.Contains(t, ve.Problems[0].Error(), "unknown")
+	assert.Contains(t, ve.Problems[1].Error(), "2[gospring.Test_validate_struct1].Count")
+}
+
+func Test_validate_withLoopProblem(t *testing.T) {
+	// arrange
+	beans := Beans(
+		Bean(Test_validate_struct2{}).ID("1").Factory(func(b *Test_validate_struct2) *Test_validate_struct2 {
+			return b
+		}, Ref("1")),
+		Bean(Test_validate_struct1{}).ID("2").Property("Count", "aaa"),
+	)
+
+	// action
+	e := Context().Validate(beans...)
+
+	// assert
+	var ve *ValidationError
+	require.True(t, errors.As(e, &ve))
+	require.Len(t, ve.Problems, 2)
+	var ce *CircularDependencyError
+	assert.True(t, errors.As(ve.Problems[0], &ce))
+	assert.Contains(t, ve.Problems[1].Error(), "2[gospring.Test_validate_struct1].Count")
+}
+
+func Test_validate_noInstance(t *testing.T) {
+	// arrange
+	Test_validate_created = 0
+	beans := Beans(
+		Bean(Test_validate_struct1{}).ID("1").Property("Next", Ref("2")),
+		Bean(Test_validate_struct2{}).ID("2").Singleton(),
+	)
+
+	// action
+	e := Context().Eager().Validate(beans...)
+
+	// assert
+	assert.Nil(t, e)
+	assert.Equal(t, 0, Test_validate_created)
+}
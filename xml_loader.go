@@ -0,0 +1,446 @@
+package gospring
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// LoadXML creates beans from a Spring-like XML document, e.g.
+//
+// <beans>
+//     <bean id="a_id" class="Astruct" scope="prototype" init-method="Init">
+//         <property name="IntValue" value="123"/>
+//         <property name="Asingleton" ref="b_id"/>
+//         <property name="Names">
+//             <list>
+//                 <value>a</value>
+//                 <ref bean="c_id"/>
+//             </list>
+//         </property>
+//         <property name="Handlers">
+//             <map>
+//                 <entry key="a" value-ref="handler_id"/>
+//             </map>
+//         </property>
+//     </bean>
+//     <bean id="b_id" class="Bstruct" factory-method="NewBstruct">
+//         <constructor-arg index="0" value="b"/>
+//     </bean>
+// </beans>
+//
+// Classes and factory methods are looked up from the registry.
+func LoadXML(data []byte, registry TypeRegistryI) ([]BeanI, error) {
+	return loadXML(data, "", registry)
+}
+
+// LoadXMLFile is the same as LoadXML but reads the document from a file.
+func LoadXMLFile(path string, registry TypeRegistryI) ([]BeanI, error) {
+	data, e := ioutil.ReadFile(path)
+	if e != nil {
+		return nil, fmt.Errorf("Can't read file [%v]. Caused by: %w", path, e)
+	}
+	return loadXML(data, path, registry)
+}
+
+func loadXML(data []byte, file string, registry TypeRegistryI) ([]BeanI, error) {
+
+	root, e := parseXML(data, file)
+	if e != nil {
+		return nil, e
+	}
+
+	converter := xmlConverter{file: file}
+	node, e := converter.convertBeans(root)
+	if e != nil {
+		return nil, e
+	}
+
+	reader := definitionReader{
+		registry:         registry,
+		explicitWrappers: true,
+	}
+	return reader.readBeans(node)
+}
+
+type xmlElement struct {
+	name     string
+	attrs    map[string]string
+	children []*xmlElement
+	text     string
+	line     int
+}
+
+func parseXML(data []byte, file string) (*xmlElement, error) {
+
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	line := func() int {
+		return 1 + bytes.Count(data[:decoder.InputOffset()], []byte("\n"))
+	}
+	location := func() string {
+		node := definitionNode{file: file, line: line()}
+		return node.location()
+	}
+
+	var root *xmlElement
+	stack := []*xmlElement{}
+
+	for {
+		// the line of the start of the token, since char data between
+		// tokens is a token as well
+		l := line()
+
+		token, e := decoder.Token()
+		if e == io.EOF {
+			break
+		} else if e != nil {
+			return nil, fmt.Errorf("%s: invalid XML. Caused by: %w", location(), e)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			element := &xmlElement{
+				name:  t.Name.Local,
+				attrs: make(map[string]string),
+				line:  l,
+			}
+			for _, attr := range t.Attr {
+				element.attrs[attr.Name.Local] = attr.Value
+			}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, element)
+			} else if root == nil {
+				root = element
+			}
+			stack = append(stack, element)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text += string(t)
+			}
+		}
+	}
+
+	if root == nil {
+		return nil, fmt.Errorf("%s: <beans> is expected", location())
+	}
+
+	return root, nil
+}
+
+// xmlConverter converts XML elements into definition nodes which are read
+// by definitionReader.
+type xmlConverter struct {
+	file string
+}
+
+func (converter *xmlConverter) errorf(element *xmlElement, format string, a ...interface{}) error {
+	node := definitionNode{file: converter.file, line: element.line}
+	return fmt.Errorf("%s: <%s>: %s", node.location(), element.name, fmt.Sprintf(format, a...))
+}
+
+func (converter *xmlConverter) object(element *xmlElement) *definitionNode {
+	return &definitionNode{
+		kind:   definitionObject,
+		file:   converter.file,
+		line:   element.line,
+		decode: converter.cantDecode(element),
+	}
+}
+
+func (converter *xmlConverter) array(element *xmlElement) *definitionNode {
+	return &definitionNode{
+		kind:   definitionArray,
+		file:   converter.file,
+		line:   element.line,
+		decode: converter.cantDecode(element),
+	}
+}
+
+// scalar creates a node whose text is converted into the type of the
+// target.
+func (converter *xmlConverter) scalar(element *xmlElement, text string) *definitionNode {
+	return &definitionNode{
+		kind:     definitionScalar,
+		file:     converter.file,
+		line:     element.line,
+		scalar:   text,
+		isString: true,
+		decode: func(target interface{}) error {
+			value := reflect.ValueOf(target).Elem()
+			converted, e := convertString(text, value.Type())
+			if e != nil {
+				return e
+			}
+			value.Set(converted)
+			return nil
+		},
+	}
+}
+
+func (converter *xmlConverter) cantDecode(element *xmlElement) func(interface{}) error {
+	return func(target interface{}) error {
+		return fmt.Errorf("<%s> can't be converted into [%v]", element.name, reflect.TypeOf(target).Elem())
+	}
+}
+
+func (converter *xmlConverter) wrap(element *xmlElement, key string, member *definitionNode) *definitionNode {
+	node := converter.object(element)
+	node.keys = []string{key}
+	node.members = []*definitionNode{member}
+	node.wrapper = true
+	return node
+}
+
+func (converter *xmlConverter) convertBeans(element *xmlElement) (*definitionNode, error) {
+
+	if element.name != "beans" {
+		return nil, converter.errorf(element, "<beans> is expected")
+	}
+
+	beans := converter.array(element)
+	for _, child := range element.children {
+		if child.name != "bean" {
+			return nil, converter.errorf(child, "unknown element")
+		}
+		bean, e := converter.convertBean(child)
+		if e != nil {
+			return nil, e
+		}
+		beans.items = append(beans.items, bean)
+	}
+
+	root := converter.object(element)
+	root.keys = []string{"beans"}
+	root.members = []*definitionNode{beans}
+	return root, nil
+}
+
+func (converter *xmlConverter) convertBean(element *xmlElement) (*definitionNode, error) {
+
+	bean := converter.object(element)
+	add := func(key string, member *definitionNode) {
+		bean.keys = append(bean.keys, key)
+		bean.members = append(bean.members, member)
+	}
+
+	names := make([]string, 0, len(element.attrs))
+	for name := range element.attrs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var factoryName *definitionNode
+	for _, name := range names {
+		value := element.attrs[name]
+		switch name {
+		case "id":
+			add("id", converter.scalar(element, value))
+		case "class":
+			add("type", converter.scalar(element, value))
+		case "scope":
+			add("scope", converter.scalar(element, value))
+		case "init-method":
+			add("init", converter.scalar(element, value))
+		case "destroy-method":
+			add("finalize", converter.scalar(element, value))
+		case "lazy-init":
+			switch value {
+			case "default":
+			case "true", "false":
+				add("lazy", converter.scalar(element, value))
+			default:
+				return nil, converter.errorf(element, "invalid lazy-init [%s]", value)
+			}
+		case "factory-method":
+			factoryName = converter.scalar(element, value)
+		default:
+			return nil, converter.errorf(element, "unknown attribute [%s]", name)
+		}
+	}
+
+	properties := converter.object(element)
+	type indexedArg struct {
+		index int
+		node  *definitionNode
+	}
+	args := []indexedArg{}
+
+	for _, child := range element.children {
+		switch child.name {
+		case "property":
+			name, present := child.attrs["name"]
+			if !present {
+				return nil, converter.errorf(child, "attribute [name] is required")
+			}
+			value, e := converter.convertValueOf(child, "name")
+			if e != nil {
+				return nil, e
+			}
+			properties.keys = append(properties.keys, name)
+			properties.members = append(properties.members, value)
+		case "constructor-arg":
+			index := len(args)
+			if s, present := child.attrs["index"]; present {
+				i, e := strconv.Atoi(s)
+				if e != nil || i < 0 {
+					return nil, converter.errorf(child, "invalid index [%s]", s)
+				}
+				index = i
+			}
+			value, e := converter.convertValueOf(child, "index")
+			if e != nil {
+				return nil, e
+			}
+			args = append(args, indexedArg{index: index, node: value})
+		default:
+			return nil, converter.errorf(child, "unknown element")
+		}
+	}
+
+	if len(args) > 0 && factoryName == nil {
+		return nil, converter.errorf(element, "<constructor-arg> needs attribute [factory-method]")
+	}
+
+	if factoryName != nil {
+		sort.SliceStable(args, func(i, j int) bool { return args[i].index < args[j].index })
+		argsNode := converter.array(element)
+		for i, arg := range args {
+			if arg.index != i {
+				return nil, converter.errorf(element, "<constructor-arg> with index [%d] is missing", i)
+			}
+			argsNode.items = append(argsNode.items, arg.node)
+		}
+		factory := converter.object(element)
+		factory.keys = []string{"name", "args"}
+		factory.members = []*definitionNode{factoryName, argsNode}
+		add("factory", factory)
+	}
+
+	if len(properties.keys) > 0 {
+		add("properties", properties)
+	}
+
+	return bean, nil
+}
+
+// convertValueOf converts the value of <property> or <constructor-arg>,
+// which is given by the attribute "value" or "ref", or a child element.
+// ignored is the attribute which isn't a part of the value.
+func (converter *xmlConverter) convertValueOf(element *xmlElement, ignored string) (*definitionNode, error) {
+
+	for name := range element.attrs {
+		switch name {
+		case "value", "ref", ignored:
+		default:
+			return nil, converter.errorf(element, "unknown attribute [%s]", name)
+		}
+	}
+
+	value, hasValue := element.attrs["value"]
+	ref, hasRef := element.attrs["ref"]
+
+	switch {
+	case hasValue && hasRef:
+		return nil, converter.errorf(element, "attributes [value] and [ref] can't be used together")
+	case (hasValue || hasRef) && len(element.children) > 0:
+		return nil, converter.errorf(element, "attributes [value] or [ref] can't be used with child elements")
+	case hasValue:
+		return converter.scalar(element, value), nil
+	case hasRef:
+		return converter.wrap(element, definitionRef, converter.scalar(element, ref)), nil
+	case len(element.children) != 1:
+		return nil, converter.errorf(element, "exact one of attributes [value], [ref] or a child element is required")
+	default:
+		return converter.convertValue(element.children[0])
+	}
+}
+
+func (converter *xmlConverter) convertValue(element *xmlElement) (*definitionNode, error) {
+
+	switch element.name {
+	case "value":
+		if len(element.children) > 0 {
+			return nil, converter.errorf(element, "child elements aren't allowed")
+		}
+		return converter.scalar(element, strings.TrimSpace(element.text)), nil
+
+	case "ref":
+		id, present := element.attrs["bean"]
+		if !present {
+			return nil, converter.errorf(element, "attribute [bean] is required")
+		}
+		return converter.wrap(element, definitionRef, converter.scalar(element, id)), nil
+
+	case "bean":
+		bean, e := converter.convertBean(element)
+		if e != nil {
+			return nil, e
+		}
+		return converter.wrap(element, definitionBean, bean), nil
+
+	case "list", "set", "array":
+		list := converter.array(element)
+		for _, child := range element.children {
+			item, e := converter.convertValue(child)
+			if e != nil {
+				return nil, e
+			}
+			list.items = append(list.items, item)
+		}
+		return list, nil
+
+	case "map":
+		m := converter.object(element)
+		for _, child := range element.children {
+			if child.name != "entry" {
+				return nil, converter.errorf(child, "<entry> is expected")
+			}
+			key, present := child.attrs["key"]
+			if !present {
+				return nil, converter.errorf(child, "attribute [key] is required")
+			}
+			if m.member(key) != nil {
+				return nil, converter.errorf(child, "key [%s] is duplicated", key)
+			}
+			value, e := converter.convertEntry(child)
+			if e != nil {
+				return nil, e
+			}
+			m.keys = append(m.keys, key)
+			m.members = append(m.members, value)
+		}
+		return m, nil
+
+	default:
+		return nil, converter.errorf(element, "unknown element")
+	}
+}
+
+func (converter *xmlConverter) convertEntry(element *xmlElement) (*definitionNode, error) {
+
+	for name := range element.attrs {
+		switch name {
+		case "key", "value", "value-ref":
+		default:
+			return nil, converter.errorf(element, "unknown attribute [%s]", name)
+		}
+	}
+
+	if ref, present := element.attrs["value-ref"]; present {
+		if _, hasValue := element.attrs["value"]; hasValue || len(element.children) > 0 {
+			return nil, converter.errorf(element, "attribute [value-ref] can't be used with others")
+		}
+		return converter.wrap(element, definitionRef, converter.scalar(element, ref)), nil
+	}
+
+	return converter.convertValueOf(element, "key")
+}
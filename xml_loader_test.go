@@ -0,0 +1,205 @@
+package gospring
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Test_LoadXML_struct1 struct {
+	Int     int
+	Ref     *Test_LoadXML_struct2
+	Inner   *Test_LoadXML_struct2
+	List    []*Test_LoadXML_struct2
+	Strings []string
+	Map     map[string]*Test_LoadXML_struct2
+	IntMap  map[int]int
+	Labels  map[string]string
+}
+
+type Test_LoadXML_struct2 struct {
+	Name string
+}
+
+type Test_LoadXML_initStruct struct {
+	Inited bool
+}
+
+func (s *Test_LoadXML_initStruct) Start() {
+	s.Inited = true
+}
+
+func NewTest_LoadXML_struct2(name string, suffix string) *Test_LoadXML_struct2 {
+	return &Test_LoadXML_struct2{
+		Name: name + suffix,
+	}
+}
+
+func newTest_LoadXML_registry() TypeRegistryI {
+	return NewTypeRegistry().
+		Type("com.example.Struct1", Test_LoadXML_struct1{}).
+		Type("com.example.Struct2", Test_LoadXML_struct2{}).
+		Type("com.example.InitStruct", Test_LoadXML_initStruct{}).
+		Factory("newStruct2", NewTest_LoadXML_struct2)
+}
+
+func Test_LoadXML(t *testing.T) {
+	// arrange
+	data := []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<beans>
+	<!-- comment -->
+	<bean id="1" class="com.example.Struct1" scope="prototype">
+		<property name="Int" value="123"/>
+		<property name="Ref" ref="2"/>
+		<property name="Inner">
+			<bean class="com.example.Struct2">
+				<property name="Name" value="inner"/>
+			</bean>
+		</property>
+		<property name="List">
+			<list>
+				<ref bean="2"/>
+				<ref bean="3"/>
+			</list>
+		</property>
+		<property name="Strings">
+			<list>
+				<value>a</value>
+				<value>${b:b}</value>
+			</list>
+		</property>
+		<property name="Map">
+			<map>
+				<entry key="a" value-ref="2"/>
+				<entry key="b">
+					<bean class="com.example.Struct2"/>
+				</entry>
+			</map>
+		</property>
+		<property name="IntMap">
+			<map>
+				<entry key="1" value="2"/>
+			</map>
+		</property>
+	</bean>
+	<bean id="2" class="com.example.Struct2" scope="singleton">
+		<property name="Name" value="two"/>
+	</bean>
+	<bean id="3" class="com.example.Struct2" factory-method="newStruct2">
+		<constructor-arg index="1" value="!"/>
+		<constructor-arg index="0" value="three"/>
+	</bean>
+	<bean id="4" class="com.example.InitStruct" init-method="Start" lazy-init="false"/>
+</beans>`)
+	beans, e := LoadXML(data, newTest_LoadXML_registry())
+	require.Nil(t, e)
+	ctx, e := NewApplicationContext(beans...)
+	require.Nil(t, e)
+
+	// action
+	bean, e := ctx.GetBean("1")
+
+	// assert
+	require.Nil(t, e)
+	b := bean.(*Test_LoadXML_struct1)
+	two, _ := ctx.GetBean("2")
+	four, _ := ctx.GetBean("4")
+	assert.Equal(t, 123, b.Int)
+	assert.True(t, b.Ref == two)
+	assert.Equal(t, "inner", b.Inner.Name)
+	require.Len(t, b.List, 2)
+	assert.True(t, b.List[0] == two)
+	assert.Equal(t, "three!", b.List[1].Name)
+	assert.Equal(t, []string{"a", "b"}, b.Strings)
+	assert.True(t, b.Map["a"] == two)
+	assert.NotNil(t, b.Map["b"])
+	assert.Equal(t, map[int]int{1: 2}, b.IntMap)
+	assert.True(t, four.(*Test_LoadXML_initStruct).Inited)
+}
+
+func Test_LoadXML_mapLikeWrapper(t *testing.T) {
+	// arrange
+	data := []byte(`<beans>
+	<bean id="1" class="com.example.Struct1">
+		<property name="Labels">
+			<map>
+				<entry key="ref" value="x"/>
+			</map>
+		</property>
+	</bean>
+	<bean id="2" class="com.example.Struct1">
+		<property name="Labels" ref="3"/>
+	</bean>
+</beans>`)
+	beans, e := LoadXML(data, newTest_LoadXML_registry())
+	require.Nil(t, e)
+	beans = append(beans, Beans(
+		Bean(map[string]string{}).ID("3").Factory(func() *map[string]string {
+			return &map[string]string{"a": "b"}
+		}),
+	)...)
+	ctx, e := NewApplicationContext(beans...)
+	require.Nil(t, e)
+
+	// action
+	bean1, e1 := ctx.GetBean("1")
+	bean2, e2 := ctx.GetBean("2")
+
+	// assert
+	require.Nil(t, e1)
+	require.Nil(t, e2)
+	assert.Equal(t, map[string]string{"ref": "x"}, bean1.(*Test_LoadXML_struct1).Labels)
+	assert.Equal(t, map[string]string{"a": "b"}, bean2.(*Test_LoadXML_struct1).Labels)
+}
+
+func Test_LoadXML_errors(t *testing.T) {
+	// arrange
+	cases := []struct {
+		data     string
+		expected string
+	}{
+		{"<aaa/>", "line 1: <aaa>: <beans> is expected"},
+		{"<beans>\n<aaa/></beans>", "line 2: <aaa>: unknown element"},
+		{"<beans>\n<bean class=\"aaa\"/></beans>", "line 2: $.beans[0].type: unknown type [aaa]"},
+		{"<beans>\n<bean class=\"com.example.Struct2\" aaa=\"1\"/></beans>", "line 2: <bean>: unknown attribute [aaa]"},
+		{"<beans>\n<bean class=\"com.example.Struct2\">\n<property name=\"Aaa\" value=\"1\"/></bean></beans>", "line 3: $.beans[0].properties.Aaa: type"},
+		{"<beans>\n<bean class=\"com.example.Struct1\">\n<property name=\"Int\" value=\"a\"/></bean></beans>", "line 3: $.beans[0].properties.Int: can't decode"},
+		{"<beans>\n<bean class=\"com.example.Struct1\">\n<property name=\"Int\" value=\"1\" ref=\"a\"/></bean></beans>", "line 3: <property>: attributes [value] and [ref]"},
+		{"<beans>\n<bean class=\"com.example.Struct1\">\n<property name=\"Int\"/></bean></beans>", "line 3: <property>: exact one"},
+		{"<beans>\n<bean class=\"com.example.Struct2\">\n<constructor-arg value=\"1\"/></bean></beans>", "line 2: <bean>: <constructor-arg> needs"},
+		{"<beans>\n<bean class=\"com.example.Struct2\" factory-method=\"newStruct2\">\n<constructor-arg index=\"1\" value=\"1\"/></bean></beans>", "line 2: <bean>: <constructor-arg> with index [0] is missing"},
+		{"<beans>\n<bean class=\"com.example.Struct2\" lazy-init=\"aaa\"/></beans>", "line 2: <bean>: invalid lazy-init"},
+		{"<beans>\n<bean>", "invalid XML"},
+	}
+
+	for _, c := range cases {
+		// action
+		beans, e := LoadXML([]byte(c.data), newTest_LoadXML_registry())
+
+		// assert
+		assert.Nil(t, beans, c.data)
+		if assert.NotNil(t, e, c.data) {
+			assert.Contains(t, e.Error(), c.expected, c.data)
+		}
+	}
+}
+
+func Test_LoadXMLFile(t *testing.T) {
+	// arrange
+	file, e := ioutil.TempFile("", "gospring")
+	require.Nil(t, e)
+	defer os.Remove(file.Name())
+	file.WriteString("<beans>\n<bean id=\"1\" class=\"aaa\"/></beans>")
+	file.Close()
+
+	// action
+	beans, e := LoadXMLFile(file.Name(), newTest_LoadXML_registry())
+
+	// assert
+	assert.Nil(t, beans)
+	require.NotNil(t, e)
+	assert.Contains(t, e.Error(), file.Name()+":2:")
+}
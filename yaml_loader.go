@@ -0,0 +1,278 @@
+package gospring
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// LoadYAML creates beans from YAML documents like
+//
+// import:
+//     - common.yaml
+//     - beans/*.yaml
+// beans:
+//     - id: a_id
+//       type: Astruct
+//       properties:
+//           IntValue: 123
+//           Asingleton: {ref: b_id}
+//     - id: b_id
+//       type: Bstruct
+// ---
+// beans:
+//     - ...
+//
+// The schema of beans is the same as LoadJSON(...). Imported files are
+// relative to the current directory, and their beans are placed before the
+// beans of the document importing them. A file imported more than once, e.g.
+// by two imported files, is loaded only the first time. Anchors, aliases and merge keys
+// ("<<") can be used to reuse definitions.
+func LoadYAML(data []byte, registry TypeRegistryI) ([]BeanI, error) {
+	loader := yamlLoader{
+		reader:  definitionReader{registry: registry},
+		loading: make(map[string]bool),
+		loaded:  make(map[string]bool),
+	}
+	return loader.load(data, "", ".")
+}
+
+// LoadYAMLFile is the same as LoadYAML but reads documents from a file.
+// Imported files are relative to the directory of the file.
+func LoadYAMLFile(path string, registry TypeRegistryI) ([]BeanI, error) {
+	loader := yamlLoader{
+		reader:  definitionReader{registry: registry},
+		loading: make(map[string]bool),
+		loaded:  make(map[string]bool),
+	}
+	return loader.loadFile(path)
+}
+
+type yamlLoader struct {
+	reader definitionReader
+
+	// loading are absolute paths of files being loaded, which are used to
+	// detect import loops.
+	loading map[string]bool
+
+	// loaded are absolute paths of files which have been loaded, so a file
+	// imported twice doesn't define its beans twice.
+	loaded map[string]bool
+}
+
+func (loader *yamlLoader) loadFile(path string) ([]BeanI, error) {
+
+	abs, e := filepath.Abs(path)
+	if e != nil {
+		return nil, fmt.Errorf("Can't get absolute path of [%v]. Caused by: %w", path, e)
+	}
+	if loader.loading[abs] {
+		return nil, fmt.Errorf("File [%v] is imported recursively", path)
+	}
+	if loader.loaded[abs] {
+		return nil, nil
+	}
+	loader.loaded[abs] = true
+	loader.loading[abs] = true
+	defer delete(loader.loading, abs)
+
+	data, e := ioutil.ReadFile(path)
+	if e != nil {
+		return nil, fmt.Errorf("Can't read file [%v]. Caused by: %w", path, e)
+	}
+
+	return loader.load(data, path, filepath.Dir(path))
+}
+
+func (loader *yamlLoader) load(data []byte, file string, dir string) ([]BeanI, error) {
+
+	name := file
+	if name == "" {
+		name = "YAML"
+	}
+
+	beans := []BeanI{}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+
+	for {
+		var document yaml.Node
+		if e := decoder.Decode(&document); e == io.EOF {
+			break
+		} else if e != nil {
+			return nil, fmt.Errorf("%s: invalid YAML. Caused by: %w", name, e)
+		}
+
+		root := convertYAMLNode(&document, file)
+		if root.kind == definitionNull {
+			continue
+		}
+		if root.kind != definitionObject {
+			return nil, definitionError(root, "$", "an object with \"beans\" is expected")
+		}
+
+		// a document without "import"
+		doc := &definitionNode{
+			kind:   definitionObject,
+			file:   root.file,
+			line:   root.line,
+			column: root.column,
+		}
+		for i, key := range root.keys {
+			if key == "import" {
+				imported, e := loader.loadImports(root.members[i], dir)
+				if e != nil {
+					return nil, e
+				}
+				beans = append(beans, imported...)
+				continue
+			}
+			doc.keys = append(doc.keys, key)
+			doc.members = append(doc.members, root.members[i])
+		}
+
+		bs, e := loader.reader.readBeans(doc)
+		if e != nil {
+			return nil, e
+		}
+		beans = append(beans, bs...)
+	}
+
+	return beans, nil
+}
+
+func (loader *yamlLoader) loadImports(node *definitionNode, dir string) ([]BeanI, error) {
+
+	var patterns []string
+	switch node.kind {
+	case definitionScalar:
+		patterns = []string{node.scalar}
+	case definitionArray:
+		for i, item := range node.items {
+			pattern, e := loader.reader.readString(item, fmt.Sprintf("$.import[%d]", i))
+			if e != nil {
+				return nil, e
+			}
+			patterns = append(patterns, pattern)
+		}
+	default:
+		return nil, definitionError(node, "$.import", "a string or an array is expected")
+	}
+
+	beans := []BeanI{}
+
+	for _, pattern := range patterns {
+		if !filepath.IsAbs(pattern) {
+			pattern = filepath.Join(dir, pattern)
+		}
+
+		paths, e := filepath.Glob(pattern)
+		if e != nil {
+			return nil, definitionError(node, "$.import", "invalid pattern [%s]. Caused by: %v", pattern, e)
+		}
+		if len(paths) == 0 && !strings.ContainsAny(pattern, "*?[") {
+			return nil, definitionError(node, "$.import", "file [%s] doesn't exist", pattern)
+		}
+		sort.Strings(paths)
+
+		for _, path := range paths {
+			bs, e := loader.loadFile(path)
+			if e != nil {
+				return nil, fmt.Errorf("%s: can't import [%s]. Caused by: %w", node.location(), path, e)
+			}
+			beans = append(beans, bs...)
+		}
+	}
+
+	return beans, nil
+}
+
+func convertYAMLNode(node *yaml.Node, file string) *definitionNode {
+
+	if node.Kind == yaml.DocumentNode {
+		if len(node.Content) == 0 {
+			return &definitionNode{kind: definitionNull, file: file, line: node.Line, column: node.Column}
+		}
+		return convertYAMLNode(node.Content[0], file)
+	}
+
+	if node.Kind == yaml.AliasNode {
+		converted := convertYAMLNode(node.Alias, file)
+		alias := *converted
+		alias.line, alias.column = node.Line, node.Column
+		return &alias
+	}
+
+	converted := &definitionNode{
+		file:   file,
+		line:   node.Line,
+		column: node.Column,
+		decode: node.Decode,
+	}
+
+	switch node.Kind {
+	case yaml.ScalarNode:
+		if node.ShortTag() == "!!null" {
+			converted.kind = definitionNull
+		} else {
+			converted.kind = definitionScalar
+			converted.scalar = node.Value
+			converted.isString = node.ShortTag() == "!!str"
+		}
+	case yaml.SequenceNode:
+		converted.kind = definitionArray
+		for _, item := range node.Content {
+			converted.items = append(converted.items, convertYAMLNode(item, file))
+		}
+	case yaml.MappingNode:
+		converted.kind = definitionObject
+		mergeYAMLMapping(converted, node, file)
+	}
+
+	return converted
+}
+
+// mergeYAMLMapping adds members of the mapping into the node. Members from
+// merge keys ("<<") don't override explicit ones.
+func mergeYAMLMapping(converted *definitionNode, node *yaml.Node, file string) {
+
+	merges := []*yaml.Node{}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+
+		if key.ShortTag() == "!!merge" {
+			if value.Kind == yaml.SequenceNode {
+				merges = append(merges, value.Content...)
+			} else {
+				merges = append(merges, value)
+			}
+			continue
+		}
+
+		converted.keys = append(converted.keys, key.Value)
+		converted.members = append(converted.members, convertYAMLNode(value, file))
+	}
+
+	for _, merge := range merges {
+		for merge.Kind == yaml.AliasNode {
+			merge = merge.Alias
+		}
+		if merge.Kind != yaml.MappingNode {
+			continue
+		}
+		m := &definitionNode{}
+		mergeYAMLMapping(m, merge, file)
+		for i, key := range m.keys {
+			if converted.member(key) == nil {
+				converted.keys = append(converted.keys, key)
+				converted.members = append(converted.members, m.members[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,248 @@
+package gospring
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Test_LoadYAML_struct struct {
+	Name    string
+	Timeout time.Duration
+	Next    *Test_LoadYAML_struct
+	Names   []string
+}
+
+func newTest_LoadYAML_registry() TypeRegistryI {
+	return NewTypeRegistry().
+		Type("struct", Test_LoadYAML_struct{})
+}
+
+func writeTest_LoadYAML_files(t *testing.T, files map[string]string) string {
+	dir, e := ioutil.TempDir("", "gospring")
+	require.Nil(t, e)
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.Nil(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.Nil(t, ioutil.WriteFile(path, []byte(content), 0644))
+	}
+	return dir
+}
+
+func Test_LoadYAML_unknownField(t *testing.T) {
+	// arrange
+	data := []byte("beans: []\n---\naaa: 1\n")
+
+	// action
+	beans, e := LoadYAML(data, newTest_LoadYAML_registry())
+
+	// assert
+	assert.Nil(t, beans)
+	require.NotNil(t, e)
+	assert.Contains(t, e.Error(), "line 3: $.aaa: unknown field")
+}
+
+func Test_LoadYAML_anchorsAndDocuments(t *testing.T) {
+	// arrange
+	data := []byte(`
+beans:
+  - &base
+    id: "1"
+    type: struct
+    properties: &props
+      Timeout: 1s
+      Names: [a, b]
+      Name: one
+      Next: {ref: "2"}
+  - <<: *base
+    id: "3"
+    properties:
+      <<: *props
+      Name: three
+---
+beans:
+  - id: "2"
+    type: struct
+    scope: prototype
+    properties:
+      Name: ${name:two}
+`)
+	beans, e := LoadYAML(data, newTest_LoadYAML_registry())
+	require.Nil(t, e)
+	ctx, e := NewApplicationContext(beans...)
+	require.Nil(t, e)
+
+	// action
+	one, e1 := ctx.GetBean("1")
+	three, e3 := ctx.GetBean("3")
+
+	// assert
+	require.Nil(t, e1)
+	require.Nil(t, e3)
+	b1 := one.(*Test_LoadYAML_struct)
+	b3 := three.(*Test_LoadYAML_struct)
+	assert.Equal(t, "one", b1.Name)
+	assert.Equal(t, time.Second, b1.Timeout)
+	assert.Equal(t, []string{"a", "b"}, b1.Names)
+	assert.Equal(t, "two", b1.Next.Name)
+	assert.Equal(t, "three", b3.Name)
+	assert.Equal(t, time.Second, b3.Timeout)
+	assert.Equal(t, "two", b3.Next.Name)
+}
+
+func Test_LoadYAMLFile_import(t *testing.T) {
+	// arrange
+	dir := writeTest_LoadYAML_files(t, map[string]string{
+		"main.yaml": `
+import:
+  - common.yaml
+  - beans/*.yaml
+beans:
+  - {id: main, type: struct}
+`,
+		"common.yaml": `
+beans:
+  - {id: common, type: struct}
+`,
+		"beans/b.yaml": `
+beans:
+  - {id: b, type: struct}
+`,
+		"beans/a.yaml": `
+import: ../other/c.yaml
+beans:
+  - {id: a, type: struct}
+`,
+		"other/c.yaml": `
+beans:
+  - {id: c, type: struct}
+`,
+	})
+	defer os.RemoveAll(dir)
+
+	// action
+	beans, e := LoadYAMLFile(filepath.Join(dir, "main.yaml"), newTest_LoadYAML_registry())
+
+	// assert
+	require.Nil(t, e)
+	ids := []string{}
+	for _, bean := range beans {
+		ids = append(ids, *bean.GetID())
+	}
+	assert.Equal(t, []string{"common", "c", "a", "b", "main"}, ids)
+}
+
+func Test_LoadYAMLFile_importTwice(t *testing.T) {
+	// arrange
+	dir := writeTest_LoadYAML_files(t, map[string]string{
+		"main.yaml": `
+import: [a.yaml, b.yaml]
+beans:
+  - {id: main, type: struct}
+`,
+		"a.yaml": `
+import: common.yaml
+beans:
+  - {id: a, type: struct}
+`,
+		"b.yaml": `
+import: common.yaml
+beans:
+  - {id: b, type: struct}
+`,
+		"common.yaml": `
+beans:
+  - {id: common, type: struct}
+`,
+	})
+	defer os.RemoveAll(dir)
+
+	// action
+	beans, e := LoadYAMLFile(filepath.Join(dir, "main.yaml"), newTest_LoadYAML_registry())
+
+	// assert
+	require.Nil(t, e)
+	ids := []string{}
+	for _, bean := range beans {
+		ids = append(ids, *bean.GetID())
+	}
+	assert.Equal(t, []string{"common", "a", "b", "main"}, ids)
+}
+
+func Test_LoadYAMLFile_importLoop(t *testing.T) {
+	// arrange
+	dir := writeTest_LoadYAML_files(t, map[string]string{
+		"a.yaml": "import: b.yaml\n",
+		"b.yaml": "import: a.yaml\n",
+	})
+	defer os.RemoveAll(dir)
+
+	// action
+	beans, e := LoadYAMLFile(filepath.Join(dir, "a.yaml"), newTest_LoadYAML_registry())
+
+	// assert
+	assert.Nil(t, beans)
+	require.NotNil(t, e)
+	assert.Contains(t, e.Error(), "recursively")
+}
+
+func Test_LoadYAMLFile_importNotExist(t *testing.T) {
+	// arrange
+	dir := writeTest_LoadYAML_files(t, map[string]string{
+		"a.yaml": "import: b.yaml\n",
+	})
+	defer os.RemoveAll(dir)
+
+	// action
+	beans, e := LoadYAMLFile(filepath.Join(dir, "a.yaml"), newTest_LoadYAML_registry())
+
+	// assert
+	assert.Nil(t, beans)
+	assert.NotNil(t, e)
+}
+
+func Test_LoadYAMLFile_errorLocation(t *testing.T) {
+	// arrange
+	dir := writeTest_LoadYAML_files(t, map[string]string{
+		"a.yaml": "import: b.yaml\n",
+		"b.yaml": "beans:\n  - id: b\n    type: aaa\n",
+	})
+	defer os.RemoveAll(dir)
+
+	// action
+	beans, e := LoadYAMLFile(filepath.Join(dir, "a.yaml"), newTest_LoadYAML_registry())
+
+	// assert
+	assert.Nil(t, beans)
+	require.NotNil(t, e)
+	assert.Contains(t, e.Error(), filepath.Join(dir, "b.yaml")+":3: $.beans[0].type: unknown type [aaa]")
+}
+
+func Test_LoadYAML_invalid(t *testing.T) {
+	// arrange
+	data := []byte("beans: [\n")
+
+	// action
+	beans, e := LoadYAML(data, newTest_LoadYAML_registry())
+
+	// assert
+	assert.Nil(t, beans)
+	assert.NotNil(t, e)
+}
+
+func Test_LoadYAML_emptyDocument(t *testing.T) {
+	// arrange
+	data := []byte("---\n---\nbeans: []\n")
+
+	// action
+	beans, e := LoadYAML(data, newTest_LoadYAML_registry())
+
+	// assert
+	assert.Nil(t, e)
+	assert.Empty(t, beans)
+}